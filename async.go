@@ -0,0 +1,252 @@
+package grequests
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// doAsyncRequest sends the request within a goroutine. The channel is buffered so the
+// goroutine won't leak if the response is never received
+func doAsyncRequest(requestVerb, url string, ro *RequestOptions) chan *Response {
+	responseChan := make(chan *Response, 1)
+
+	go func() {
+		resp, _ := doRegularRequest(requestVerb, url, ro)
+		responseChan <- resp
+	}()
+
+	return responseChan
+}
+
+// GetAsync takes 2 parameters and returns a channel that will receive the Response once the request has
+// completed. Any error is stored within the `Error` field of the Response. These two options are:
+//  1. A URL
+//  2. A RequestOptions struct
+//
+// If you do not intend to use the `RequestOptions` you can just pass nil
+func GetAsync(url string, ro *RequestOptions) chan *Response {
+	return doAsyncRequest("GET", url, ro)
+}
+
+// PutAsync works like GetAsync but sends a PUT request
+func PutAsync(url string, ro *RequestOptions) chan *Response {
+	return doAsyncRequest("PUT", url, ro)
+}
+
+// PatchAsync works like GetAsync but sends a PATCH request
+func PatchAsync(url string, ro *RequestOptions) chan *Response {
+	return doAsyncRequest("PATCH", url, ro)
+}
+
+// DeleteAsync works like GetAsync but sends a DELETE request
+func DeleteAsync(url string, ro *RequestOptions) chan *Response {
+	return doAsyncRequest("DELETE", url, ro)
+}
+
+// PostAsync works like GetAsync but sends a POST request
+func PostAsync(url string, ro *RequestOptions) chan *Response {
+	return doAsyncRequest("POST", url, ro)
+}
+
+// HeadAsync works like GetAsync but sends a HEAD request
+func HeadAsync(url string, ro *RequestOptions) chan *Response {
+	return doAsyncRequest("HEAD", url, ro)
+}
+
+// OptionsAsync works like GetAsync but sends an OPTIONS request
+func OptionsAsync(url string, ro *RequestOptions) chan *Response {
+	return doAsyncRequest("OPTIONS", url, ro)
+}
+
+// defaultPoolWorkers is the amount of requests a Pool sends at once by default
+const defaultPoolWorkers = 10
+
+// ErrPoolStopped is the error of the requests that weren't sent by a FailFast Pool because an
+// earlier request failed
+var ErrPoolStopped = errors.New("grequests: Request was not sent because an earlier request failed")
+
+// RequestSpec describes a single request that is sent by a Pool, Map or MapUnordered
+type RequestSpec struct {
+	// Method is the HTTP method of the request (GET by default)
+	Method string
+
+	// URL is the URL of the request
+	URL string
+
+	// RequestOptions are the options of the request (may be nil)
+	RequestOptions *RequestOptions
+}
+
+// PoolResult is the outcome of a RequestSpec
+type PoolResult struct {
+	// Index is the position of the request within the requests given to the Pool
+	Index int
+
+	// Request is the request that was sent
+	Request RequestSpec
+
+	// Response is the response of the request. It is never nil
+	Response *Response
+
+	// Error is the error returned by the request (if any)
+	Error error
+}
+
+// Pool sends many requests concurrently while limiting the amount of requests that are in flight at once
+type Pool struct {
+	// Workers is the maximum amount of requests that are sent at once. The default is 10
+	Workers int
+
+	// Session (if set) is used to send the requests
+	Session *Session
+
+	// FailFast stops sending requests once a request returns an error (set RaiseForStatus on
+	// the RequestOptions to treat a response that isn't 2xx as an error). The requests that are
+	// in flight complete and the remaining results contain ErrPoolStopped
+	FailFast bool
+}
+
+// NewPool returns a Pool that sends up to workers requests at once
+func NewPool(workers int) *Pool {
+	return &Pool{Workers: workers}
+}
+
+// Do sends all of the requests and waits for them to complete. The results are returned in the same
+// order as the requests. Once the context is done no more requests are sent (the remaining results
+// contain the error of the context). The context is also used by any request that doesn't have a Context
+func (p *Pool) Do(ctx context.Context, requests ...RequestSpec) []PoolResult {
+	results := make([]PoolResult, len(requests))
+
+	p.run(ctx, requests, func(result PoolResult) {
+		results[result.Index] = result
+	})
+
+	return results
+}
+
+// DoUnordered works like Do except that the results are delivered on the channel as soon as each
+// request completes. The channel is closed once every result has been delivered
+func (p *Pool) DoUnordered(ctx context.Context, requests ...RequestSpec) <-chan PoolResult {
+	// The channel can hold every result so the requests complete even if they are never received
+	results := make(chan PoolResult, len(requests))
+
+	go func() {
+		defer close(results)
+
+		p.run(ctx, requests, func(result PoolResult) {
+			results <- result
+		})
+	}()
+
+	return results
+}
+
+// Map sends the requests (which may use different methods, URLs and options) with up to
+// concurrency requests in flight at once and returns the results in the same order as the
+// requests (like the map of Python's grequests)
+func Map(requests []RequestSpec, concurrency int) []PoolResult {
+	return NewPool(concurrency).Do(context.Background(), requests...)
+}
+
+// MapUnordered works like Map except that the results are delivered as soon as each request
+// completes (like the imap_unordered of Python's grequests)
+func MapUnordered(requests []RequestSpec, concurrency int) <-chan PoolResult {
+	return NewPool(concurrency).DoUnordered(context.Background(), requests...)
+}
+
+// run sends the requests calling deliver (from several goroutines, but never at once) with each result
+func (p *Pool) run(ctx context.Context, requests []RequestSpec, deliver func(PoolResult)) {
+	workers := p.Workers
+
+	if workers <= 0 {
+		workers = defaultPoolWorkers
+	}
+
+	semaphore := make(chan struct{}, workers)
+
+	// stopped is closed once a request fails (if the pool is FailFast)
+	stopped := make(chan struct{})
+
+	var wg sync.WaitGroup
+	var deliverMu sync.Mutex
+
+	complete := func(result PoolResult) {
+		deliverMu.Lock()
+		defer deliverMu.Unlock()
+
+		if result.Error != nil && p.FailFast && !isClosed(stopped) {
+			close(stopped)
+		}
+
+		deliver(result)
+	}
+
+	for i, request := range requests {
+		result := PoolResult{Index: i, Request: request}
+
+		acquired := false
+
+		select {
+		case semaphore <- struct{}{}:
+			acquired = true
+		case <-ctx.Done():
+		case <-stopped:
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			result.Response, result.Error = buildResponse(nil, ctx.Err())
+		case isClosed(stopped):
+			result.Response, result.Error = buildResponse(nil, ErrPoolStopped)
+		}
+
+		if result.Error != nil {
+			// The slot may have been acquired just as the pool was stopped
+			if acquired {
+				<-semaphore
+			}
+
+			complete(result)
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(result PoolResult) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			result.Response, result.Error = p.send(ctx, result.Request)
+			complete(result)
+		}(result)
+	}
+
+	wg.Wait()
+}
+
+// send sends a single request of the pool
+func (p *Pool) send(ctx context.Context, request RequestSpec) (*Response, error) {
+	method := request.Method
+
+	if method == "" {
+		method = "GET"
+	}
+
+	ro := &RequestOptions{}
+
+	if request.RequestOptions != nil {
+		copied := *request.RequestOptions
+		ro = &copied
+	}
+
+	if ro.Context == nil {
+		ro.Context = ctx
+	}
+
+	if p.Session != nil {
+		return p.Session.doRequest(method, request.URL, ro)
+	}
+
+	return doRegularRequest(method, request.URL, ro)
+}
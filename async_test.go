@@ -0,0 +1,174 @@
+package grequests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAsyncRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Method))
+	}))
+	defer ts.Close()
+
+	get, post := GetAsync(ts.URL, nil), PostAsync(ts.URL, nil)
+
+	if resp := <-get; resp.Error != nil || resp.String() != "GET" {
+		t.Error("Invalid GET response: ", resp.Error)
+	}
+
+	if resp := <-post; resp.Error != nil || resp.String() != "POST" {
+		t.Error("Invalid POST response: ", resp.Error)
+	}
+
+	if resp := <-GetAsync("%../dir/", nil); resp.Error == nil {
+		t.Error("Invalid URL did not return an error")
+	}
+}
+
+func TestPoolLimitsConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(r.URL.Query().Get("i")))
+	}))
+	defer ts.Close()
+
+	requests := make([]RequestSpec, 20)
+
+	for i := range requests {
+		requests[i] = RequestSpec{URL: ts.URL, RequestOptions: &RequestOptions{Params: map[string]string{"i": strconv.Itoa(i)}}}
+	}
+
+	results := NewPool(3).Do(context.Background(), requests...)
+
+	for i, result := range results {
+		if result.Error != nil {
+			t.Fatal("Request failed: ", result.Error)
+		}
+
+		if result.Response.String() != strconv.Itoa(i) {
+			t.Error("Results are out of order: ", i, result.Response.String())
+		}
+	}
+
+	if max := atomic.LoadInt32(&maxInFlight); max > 3 {
+		t.Error("Too many requests were in flight: ", max)
+	}
+}
+
+func TestPoolStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+	}))
+	defer ts.Close()
+
+	results := NewPool(1).Do(ctx, RequestSpec{URL: ts.URL}, RequestSpec{URL: ts.URL})
+
+	if results[1].Error != context.Canceled || results[1].Response.Error != context.Canceled {
+		t.Error("Request was sent after the context was cancelled: ", results[1].Error)
+	}
+}
+
+func TestMap(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		w.Write([]byte(r.Method + " " + r.URL.Path))
+	}))
+	defer ts.Close()
+
+	requests := []RequestSpec{
+		{URL: ts.URL + "/slow"},
+		{Method: "POST", URL: ts.URL + "/users", RequestOptions: &RequestOptions{JSON: map[string]string{"a": "b"}}},
+		{Method: "DELETE", URL: ts.URL + "/users/1"},
+		{URL: "%../dir/"},
+	}
+
+	results := Map(requests, 4)
+
+	for i, expected := range []string{"GET /slow", "POST /users", "DELETE /users/1"} {
+		if results[i].Index != i || results[i].Error != nil || results[i].Response.String() != expected {
+			t.Errorf("Unexpected result %d: %v %q", i, results[i].Error, results[i].Response.String())
+		}
+	}
+
+	if results[3].Error == nil || results[3].Response.Error != results[3].Error {
+		t.Error("Expected the invalid URL to fail")
+	}
+
+	// The slow request is delivered last
+	var order []int
+
+	for result := range MapUnordered(requests, 4) {
+		order = append(order, result.Index)
+	}
+
+	if len(order) != 4 || order[3] != 0 {
+		t.Error("Unexpected order of the results: ", order)
+	}
+}
+
+func TestPoolFailFast(t *testing.T) {
+	var sent int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sent, 1)
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	ro := &RequestOptions{RaiseForStatus: true}
+	requests := []RequestSpec{{URL: ts.URL + "/ok", RequestOptions: ro}, {URL: ts.URL + "/fail", RequestOptions: ro}}
+
+	for i := 0; i < 5; i++ {
+		requests = append(requests, RequestSpec{URL: ts.URL + "/ok", RequestOptions: ro})
+	}
+
+	pool := &Pool{Workers: 1, FailFast: true}
+	results := pool.Do(context.Background(), requests...)
+
+	if results[0].Error != nil {
+		t.Error("Unexpected error: ", results[0].Error)
+	}
+
+	var non2xx *Non2xxError
+
+	if !errors.As(results[1].Error, &non2xx) {
+		t.Error("Expected the failed request to return a Non2xxError, got: ", results[1].Error)
+	}
+
+	for _, result := range results[2:] {
+		if result.Error != ErrPoolStopped {
+			t.Error("Expected the remaining requests to be stopped, got: ", result.Error)
+		}
+	}
+
+	if n := atomic.LoadInt32(&sent); n != 2 {
+		t.Error("Expected 2 requests to be sent, got: ", n)
+	}
+}
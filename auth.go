@@ -0,0 +1,90 @@
+package grequests
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tokenExpirySkew is how long before its expiry a token is refreshed so that
+// it doesn't expire while the request is in flight
+const tokenExpirySkew = 30 * time.Second
+
+// Authorizer authorizes a request e.g. by signing it or adding an access token.
+// Apply is called with every attempt of a request just before it is sent
+type Authorizer interface {
+	Apply(req *http.Request) error
+}
+
+// AuthorizerFunc is an adapter to allow the use of an ordinary function as an Authorizer
+type AuthorizerFunc func(req *http.Request) error
+
+// Apply calls f(req)
+func (f AuthorizerFunc) Apply(req *http.Request) error {
+	return f(req)
+}
+
+// TokenFunc returns a new access token along with the time it expires. A zero
+// expiry means that the token never expires
+type TokenFunc func() (token string, expiry time.Time, err error)
+
+// RefreshingBearerToken returns an Authorizer that adds the token returned by fetch as a
+// bearer token. The token is cached until shortly before it expires, at which point fetch
+// is called again. The Authorizer is safe to share across goroutines
+func RefreshingBearerToken(fetch TokenFunc) Authorizer {
+	return &refreshingBearerToken{fetch: fetch}
+}
+
+type refreshingBearerToken struct {
+	fetch TokenFunc
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func (r *refreshingBearerToken) Apply(req *http.Request) error {
+	token, err := r.currentToken()
+
+	if err != nil {
+		return err
+	}
+
+	setBearerToken(req, token)
+
+	return nil
+}
+
+// currentToken returns the cached token (refreshing it if it is about to expire)
+func (r *refreshingBearerToken) currentToken() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.token != "" && (r.expiry.IsZero() || time.Now().Add(tokenExpirySkew).Before(r.expiry)) {
+		return r.token, nil
+	}
+
+	token, expiry, err := r.fetch()
+
+	if err != nil {
+		return "", err
+	}
+
+	r.token, r.expiry = token, expiry
+
+	return token, nil
+}
+
+// setBearerToken sets the Authorization header of the request to the bearer token
+func setBearerToken(req *http.Request, token string) {
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+// authorizeRequest applies the Authorizer to the request (which happens for every attempt)
+func authorizeRequest(ro *RequestOptions, req *http.Request) error {
+	if ro.Authorizer != nil {
+		return ro.Authorizer.Apply(req)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package grequests
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newAuthorizationEchoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+}
+
+func TestBearerToken(t *testing.T) {
+	ts := newAuthorizationEchoServer()
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, &RequestOptions{BearerToken: "secret"})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "Bearer secret" {
+		t.Error("Bearer token was not sent: ", resp.String())
+	}
+}
+
+func TestAuthorizerIsApplied(t *testing.T) {
+	ts := newAuthorizationEchoServer()
+	defer ts.Close()
+
+	authorizer := AuthorizerFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Signed "+req.Method)
+		return nil
+	})
+
+	resp, err := Get(ts.URL, &RequestOptions{BearerToken: "ignored", Authorizer: authorizer})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "Signed GET" {
+		t.Error("Authorizer was not applied: ", resp.String())
+	}
+
+	authErr := errors.New("unable to sign")
+
+	if _, err := Get(ts.URL, &RequestOptions{Authorizer: AuthorizerFunc(func(*http.Request) error {
+		return authErr
+	})}); err != authErr {
+		t.Error("Expected the error of the Authorizer, got: ", err)
+	}
+}
+
+func TestRefreshingBearerToken(t *testing.T) {
+	ts := newAuthorizationEchoServer()
+	defer ts.Close()
+
+	fetches := 0
+	expiry := time.Now().Add(time.Hour)
+
+	authorizer := RefreshingBearerToken(func() (string, time.Time, error) {
+		fetches++
+		return "token" + string(rune('0'+fetches)), expiry, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		resp, err := Get(ts.URL, &RequestOptions{Authorizer: authorizer})
+
+		if err != nil {
+			t.Fatal("Request failed: ", err)
+		}
+
+		if resp.String() != "Bearer token1" {
+			t.Error("Cached token was not used: ", resp.String())
+		}
+	}
+
+	// The token is refreshed when it is about to expire
+	expiry = time.Now().Add(time.Second)
+	authorizer.(*refreshingBearerToken).expiry = expiry
+
+	resp, err := Get(ts.URL, &RequestOptions{Authorizer: authorizer})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "Bearer token2" || fetches != 2 {
+		t.Error("Token was not refreshed: ", resp.String())
+	}
+}
+
+func TestAuthorizerSignsEveryAttempt(t *testing.T) {
+	var received []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("Authorization"))
+
+		if len(received) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	var signed int
+
+	_, err := Get(ts.URL, &RequestOptions{
+		BeforeRequest: []func(*http.Request) error{
+			func(req *http.Request) error {
+				req.Header.Set("X-Date", "from-hook")
+				return nil
+			},
+		},
+		Authorizer: AuthorizerFunc(func(req *http.Request) error {
+			signed++
+			req.Header.Set("Authorization", fmt.Sprintf("Signed %s %d", req.Header.Get("X-Date"), signed))
+			return nil
+		}),
+		RetryPolicy: &BackoffRetryPolicy{Backoff: ConstantBackoff(0)},
+	})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if len(received) != 2 || received[0] != "Signed from-hook 1" || received[1] != "Signed from-hook 2" {
+		t.Error("Every attempt was not signed after the hooks: ", received)
+	}
+}
@@ -0,0 +1,246 @@
+package grequests
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	awsSigV4Algorithm  = "AWS4-HMAC-SHA256"
+	awsSigV4TimeFormat = "20060102T150405Z"
+
+	// awsUnsignedPayload is sent instead of the hash of a payload that isn't signed
+	awsUnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// ErrAWSUnhashableBody is the error returned when the body of a request that is signed with
+// AWSSigV4 can only be read once (and UnsignedPayload isn't set)
+var ErrAWSUnhashableBody = errors.New("grequests: AWS SigV4 can't hash a body that can only be read once")
+
+// AWSCredentials are the credentials used to sign requests with AWSSigV4
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// SessionToken is only needed for temporary credentials (e.g. those of an assumed role)
+	SessionToken string
+}
+
+// AWSSigV4 is an Authorizer that signs requests with AWS Signature Version 4 so that AWS
+// services (e.g. S3, API Gateway and OpenSearch) can be called without the AWS SDK:
+//
+//	resp, err := grequests.Get("https://my-bucket.s3.eu-west-1.amazonaws.com/key", &grequests.RequestOptions{
+//		Authorizer: grequests.AWSSigV4{Region: "eu-west-1", Service: "s3", Credentials: credentials},
+//	})
+//
+// The Host, Content-Type, Content-MD5 and X-Amz-* headers are signed along with the hash of the
+// payload, so the body must be rewindable (every body type of RequestOptions is, except RequestBody
+// readers other than bytes.Buffer, bytes.Reader and strings.Reader)
+type AWSSigV4 struct {
+	// Region is the region of the service e.g. us-east-1
+	Region string
+
+	// Service is the signing name of the service e.g. s3, execute-api or es
+	Service string
+
+	// Credentials are the credentials the request is signed with
+	Credentials AWSCredentials
+
+	// UnsignedPayload skips hashing the body (it is sent as UNSIGNED-PAYLOAD instead). Only
+	// some services (such as S3) accept an unsigned payload
+	UnsignedPayload bool
+}
+
+// Apply signs the request
+func (s AWSSigV4) Apply(req *http.Request) error {
+	return s.sign(req, time.Now())
+}
+
+// sign signs the request as if it was sent at the time
+func (s AWSSigV4) sign(req *http.Request, now time.Time) error {
+	payloadHash, err := s.payloadHash(req)
+
+	if err != nil {
+		return err
+	}
+
+	now = now.UTC()
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", now.Format(awsSigV4TimeFormat))
+
+	if s.Credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.Credentials.SessionToken)
+	}
+
+	// S3 requires the hash of the payload to be sent as a header
+	if s.Service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	signedHeaders, canonicalHeaders := awsCanonicalHeaders(req)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s.canonicalURI(req.URL),
+		awsCanonicalQuery(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.Region, s.Service, "aws4_request"}, "/")
+
+	stringToSign := strings.Join([]string{
+		awsSigV4Algorithm,
+		now.Format(awsSigV4TimeFormat),
+		scope,
+		hashSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.Credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", awsSigV4Algorithm+" Credential="+s.Credentials.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+
+	return nil
+}
+
+// payloadHash returns the hex encoded SHA256 hash of the body of the request
+func (s AWSSigV4) payloadHash(req *http.Request) (string, error) {
+	if s.UnsignedPayload {
+		return awsUnsignedPayload, nil
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return hashSHA256(nil), nil
+	}
+
+	if req.GetBody == nil {
+		return "", ErrAWSUnhashableBody
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return "", err
+	}
+
+	defer body.Close()
+
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, body); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// canonicalURI returns the URI encoded path of the URL. Every service except S3 expects
+// the (already encoded) path to be encoded a second time
+func (s AWSSigV4) canonicalURI(u *url.URL) string {
+	uri := u.EscapedPath()
+
+	if s.Service == "s3" {
+		uri = u.Path
+	}
+
+	if uri == "" {
+		return "/"
+	}
+
+	segments := strings.Split(uri, "/")
+
+	for i, segment := range segments {
+		segments[i] = percentEncode(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// awsCanonicalQuery returns the query string of the URL sorted and URI encoded
+func awsCanonicalQuery(u *url.URL) string {
+	query, _ := url.ParseQuery(u.RawQuery)
+
+	params := make([]string, 0, len(query))
+
+	for _, key := range sortedValueKeys(query) {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+
+		for _, value := range values {
+			params = append(params, percentEncode(key)+"="+percentEncode(value))
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+// awsCanonicalHeaders returns the (sorted) names of the signed headers along with the
+// canonical form of those headers
+func awsCanonicalHeaders(req *http.Request) (string, string) {
+	host := req.Host
+
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{"host": strings.TrimSpace(host)}
+
+	for key, values := range req.Header {
+		name := strings.ToLower(key)
+
+		if !strings.HasPrefix(name, "x-amz-") && name != "content-type" && name != "content-md5" {
+			continue
+		}
+
+		trimmed := make([]string, len(values))
+
+		for i, value := range values {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+
+		headers[name] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	canonical := &strings.Builder{}
+
+	for _, name := range names {
+		canonical.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	return strings.Join(names, ";"), canonical.String()
+}
+
+func hashSHA256(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,121 @@
+package grequests
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The test vectors are from the AWS Signature Version 4 test suite
+var awsTestSigner = AWSSigV4{
+	Region:      "us-east-1",
+	Service:     "service",
+	Credentials: AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"},
+}
+
+var awsTestTime = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+func TestAWSSigV4TestSuite(t *testing.T) {
+	tests := []struct {
+		method    string
+		url       string
+		signature string
+	}{
+		{"GET", "https://example.amazonaws.com/", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"POST", "https://example.amazonaws.com/", "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b"},
+		{"GET", "https://example.amazonaws.com/?Param2=value2&Param1=value1", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+	}
+
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, test.url, nil)
+
+		if err := awsTestSigner.sign(req, awsTestTime); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+			"SignedHeaders=host;x-amz-date, Signature=" + test.signature
+
+		if req.Header.Get("Authorization") != expected {
+			t.Errorf("%s %s: Unexpected Authorization header: %s", test.method, test.url, req.Header.Get("Authorization"))
+		}
+
+		if req.Header.Get("X-Amz-Date") != "20150830T123600Z" {
+			t.Error("X-Amz-Date was not set: ", req.Header.Get("X-Amz-Date"))
+		}
+	}
+}
+
+func TestAWSSigV4Payload(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://example.amazonaws.com/", strings.NewReader("Param1=value1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if err := awsTestSigner.sign(req, awsTestTime); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, Signature=ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a"
+
+	if req.Header.Get("Authorization") != expected {
+		t.Error("Unexpected Authorization header: ", req.Header.Get("Authorization"))
+	}
+
+	// The body can still be sent after it has been hashed
+	if body, _ := ioutil.ReadAll(req.Body); string(body) != "Param1=value1" {
+		t.Error("Body was consumed: ", string(body))
+	}
+
+	unhashable, _ := http.NewRequest("PUT", "https://example.amazonaws.com/", ioutil.NopCloser(bytes.NewReader([]byte("a"))))
+
+	if err := awsTestSigner.sign(unhashable, awsTestTime); err != ErrAWSUnhashableBody {
+		t.Error("Expected ErrAWSUnhashableBody, got: ", err)
+	}
+}
+
+func TestAWSSigV4S3(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization") + "\n" + r.Header.Get("X-Amz-Content-Sha256") + "\n" +
+			r.Header.Get("X-Amz-Security-Token")))
+	}))
+	defer ts.Close()
+
+	signer := AWSSigV4{
+		Region:          "eu-west-1",
+		Service:         "s3",
+		Credentials:     AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "token"},
+		UnsignedPayload: true,
+	}
+
+	resp, err := Put(ts.URL+"/bucket/a key.txt", &RequestOptions{
+		RequestBody: ioutil.NopCloser(strings.NewReader("contents")),
+		Authorizer:  signer,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(resp.String(), "\n")
+
+	if len(lines) != 3 || lines[1] != "UNSIGNED-PAYLOAD" || lines[2] != "token" {
+		t.Fatal("Unexpected headers: ", resp.String())
+	}
+
+	if !strings.HasPrefix(lines[0], "AWS4-HMAC-SHA256 Credential=AKID/") ||
+		!strings.Contains(lines[0], "/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token, ") {
+		t.Error("Unexpected Authorization header: ", lines[0])
+	}
+
+	if uri := signer.canonicalURI(resp.RawResponse.Request.URL); uri != "/bucket/a%20key.txt" {
+		t.Error("Unexpected canonical URI: ", uri)
+	}
+
+	if uri := awsTestSigner.canonicalURI(resp.RawResponse.Request.URL); uri != "/bucket/a%2520key.txt" {
+		t.Error("Unexpected canonical URI: ", uri)
+	}
+}
@@ -56,3 +56,13 @@ func Head(url string, ro *RequestOptions) (*Response, error) {
 func Options(url string, ro *RequestOptions) (*Response, error) {
 	return doRegularRequest("OPTIONS", url, ro)
 }
+
+// Req takes 3 parameters and returns a Response struct. These three options are:
+// 	1. An HTTP verb (e.g. PROPFIND, REPORT, PURGE or LINK)
+// 	2. A URL
+// 	3. A RequestOptions struct
+// If you do not intend to use the `RequestOptions` you can just pass nil
+// Req is meant for verbs that don't have a function of their own, every body type is supported
+func Req(verb string, url string, ro *RequestOptions) (*Response, error) {
+	return doRegularRequest(verb, url, ro)
+}
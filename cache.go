@@ -0,0 +1,417 @@
+package grequests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCachedBodySize is the largest body that will be stored within the cache
+const maxCachedBodySize = 10 << 20
+
+// CachedResponse is a response stored within a CacheBackend
+type CachedResponse struct {
+	// StatusCode is the status code of the response
+	StatusCode int
+
+	// Header are the headers of the response
+	Header http.Header
+
+	// Body is the (raw) body of the response
+	Body []byte
+
+	// VaryHeaders are the request headers named by the Vary header of the response
+	// and VaryKey are their values within the request that produced the response
+	VaryHeaders []string
+	VaryKey     string
+
+	// StoredAt is when the response was received (or last revalidated)
+	StoredAt time.Time
+}
+
+// CacheBackend stores cached responses. Backends must be safe for concurrent use. The cache is best
+// effort so backends don't return errors – a response that can't be stored is simply not cached
+type CacheBackend interface {
+	// Get returns the response stored under the key (if there is one)
+	Get(key string) (*CachedResponse, bool)
+
+	// Set stores the response under the key
+	Set(key string, resp *CachedResponse)
+
+	// Delete removes the response stored under the key
+	Delete(key string)
+}
+
+// MemoryCache is a CacheBackend that keeps the responses in memory
+type MemoryCache struct {
+	mu        sync.RWMutex
+	responses map[string]*CachedResponse
+}
+
+// NewMemoryCache returns an empty MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{responses: map[string]*CachedResponse{}}
+}
+
+// Get implements CacheBackend
+func (c *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	resp, ok := c.responses[key]
+
+	return resp, ok
+}
+
+// Set implements CacheBackend
+func (c *MemoryCache) Set(key string, resp *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.responses[key] = resp
+}
+
+// Delete implements CacheBackend
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.responses, key)
+}
+
+// DiskCache is a CacheBackend that stores every response as a file within a directory
+type DiskCache struct {
+	// Dir is the directory the responses are stored in
+	Dir string
+}
+
+// NewDiskCache returns a DiskCache that stores the responses within dir (which is created if it doesn't exist)
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &DiskCache{Dir: dir}, nil
+}
+
+// path returns the file the key is stored in
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements CacheBackend
+func (c *DiskCache) Get(key string) (*CachedResponse, bool) {
+	b, err := ioutil.ReadFile(c.path(key))
+
+	if err != nil {
+		return nil, false
+	}
+
+	resp := &CachedResponse{}
+
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, false
+	}
+
+	return resp, true
+}
+
+// Set implements CacheBackend. The file is written atomically so a concurrent Get never sees a partial response
+func (c *DiskCache) Set(key string, resp *CachedResponse) {
+	b, err := json.Marshal(resp)
+
+	if err != nil {
+		return
+	}
+
+	tmp, err := ioutil.TempFile(c.Dir, ".tmp-")
+
+	if err != nil {
+		return
+	}
+
+	_, err = tmp.Write(b)
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Delete implements CacheBackend
+func (c *DiskCache) Delete(key string) {
+	os.Remove(c.path(key))
+}
+
+// cacheTransport serves GET requests from the cache while they are fresh and revalidates
+// stale responses using If-None-Match and If-Modified-Since
+type cacheTransport struct {
+	transport http.RoundTripper
+	backend   CacheBackend
+	keys      *CacheKeyOptions
+}
+
+// CloseIdleConnections closes the idle connections of the underlying transport
+func (c *cacheTransport) CloseIdleConnections() {
+	if closer, ok := c.transport.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+func (c *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" {
+		return c.roundTripUnsafe(req)
+	}
+
+	if req.Header.Get("Range") != "" || hasCacheDirective(req.Header, "no-store") {
+		return c.transport.RoundTrip(req)
+	}
+
+	key := c.keys.Key(req)
+
+	cached, ok := c.backend.Get(key)
+
+	if ok && varyKey(req, cached.VaryHeaders) != cached.VaryKey {
+		cached, ok = nil, false
+	}
+
+	if ok && !hasCacheDirective(req.Header, "no-cache") && isFresh(cached) {
+		return cachedHTTPResponse(req, cached), nil
+	}
+
+	outgoing := req
+
+	if ok {
+		outgoing = conditionalRequest(req, cached)
+	}
+
+	resp, err := c.transport.RoundTrip(outgoing)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+		resp.Body.Close()
+
+		// The 304 carries the updated caching headers of the response
+		revalidated := *cached
+		revalidated.Header = cached.Header.Clone()
+
+		for name, values := range resp.Header {
+			revalidated.Header[name] = values
+		}
+
+		revalidated.StoredAt = time.Now()
+
+		c.backend.Set(key, &revalidated)
+
+		return cachedHTTPResponse(req, &revalidated), nil
+	}
+
+	if isCacheable(resp) {
+		if names, cacheable := varyHeaderNames(resp.Header); cacheable {
+			resp.Body = &cachingBody{
+				ReadCloser: resp.Body,
+				store: func(body []byte) {
+					c.backend.Set(key, &CachedResponse{
+						StatusCode:  resp.StatusCode,
+						Header:      resp.Header.Clone(),
+						Body:        body,
+						VaryHeaders: names,
+						VaryKey:     varyKey(req, names),
+						StoredAt:    time.Now(),
+					})
+				},
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// roundTripUnsafe sends a request that isn't cached. A successful unsafe request (e.g. POST or DELETE)
+// invalidates the cached response of the URL
+func (c *cacheTransport) roundTripUnsafe(req *http.Request) (*http.Response, error) {
+	resp, err := c.transport.RoundTrip(req)
+
+	if err != nil || req.Method == "HEAD" || req.Method == "OPTIONS" || req.Method == "TRACE" {
+		return resp, err
+	}
+
+	if resp.StatusCode < 400 {
+		getReq := req.Clone(req.Context())
+		getReq.Method = "GET"
+
+		c.backend.Delete(c.keys.Key(getReq))
+	}
+
+	return resp, nil
+}
+
+// conditionalRequest returns a copy of the request that asks the server to only send the body if it changed
+func conditionalRequest(req *http.Request, cached *CachedResponse) *http.Request {
+	conditional := req.Clone(req.Context())
+
+	if etag := cached.Header.Get("ETag"); etag != "" && conditional.Header.Get("If-None-Match") == "" {
+		conditional.Header.Set("If-None-Match", etag)
+	}
+
+	if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" && conditional.Header.Get("If-Modified-Since") == "" {
+		conditional.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return conditional
+}
+
+// cachedHTTPResponse builds the response from the cache. The X-From-Cache header is set on the response
+func cachedHTTPResponse(req *http.Request, cached *CachedResponse) *http.Response {
+	header := cached.Header.Clone()
+	header.Set("X-From-Cache", "1")
+
+	return &http.Response{
+		Status:        strconv.Itoa(cached.StatusCode) + " " + http.StatusText(cached.StatusCode),
+		StatusCode:    cached.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+// isCacheable reports if the response may be stored. A response needs a validator or an explicit lifetime
+func isCacheable(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK || hasCacheDirective(resp.Header, "no-store") {
+		return false
+	}
+
+	if resp.ContentLength > maxCachedBodySize {
+		return false
+	}
+
+	_, hasLifetime := freshnessLifetime(resp.Header, time.Now())
+
+	return hasLifetime || resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+// isFresh reports if the cached response can be used without asking the server
+func isFresh(cached *CachedResponse) bool {
+	lifetime, ok := freshnessLifetime(cached.Header, cached.StoredAt)
+
+	return ok && time.Since(cached.StoredAt) < lifetime
+}
+
+// freshnessLifetime returns how long the response is fresh for (from the Cache-Control max-age
+// directive or the Expires header). ok is false if the response doesn't have a lifetime
+func freshnessLifetime(header http.Header, received time.Time) (time.Duration, bool) {
+	if hasCacheDirective(header, "no-cache") {
+		return 0, false
+	}
+
+	for _, directive := range cacheDirectives(header) {
+		if strings.HasPrefix(directive, "max-age=") {
+			seconds, err := strconv.ParseInt(strings.TrimPrefix(directive, "max-age="), 10, 64)
+
+			if err != nil {
+				return 0, false
+			}
+
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+
+		if err != nil {
+			return 0, true // An invalid Expires means that the response has already expired
+		}
+
+		date := received
+
+		if d, err := http.ParseTime(header.Get("Date")); err == nil {
+			date = d
+		}
+
+		return expiresAt.Sub(date), true
+	}
+
+	return 0, false
+}
+
+// cacheDirectives returns the (lower case) directives of the Cache-Control header
+func cacheDirectives(header http.Header) []string {
+	var directives []string
+
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			if directive = strings.ToLower(strings.TrimSpace(directive)); directive != "" {
+				directives = append(directives, directive)
+			}
+		}
+	}
+
+	return directives
+}
+
+// hasCacheDirective reports if the Cache-Control header contains the directive
+func hasCacheDirective(header http.Header, name string) bool {
+	for _, directive := range cacheDirectives(header) {
+		if directive == name || strings.HasPrefix(directive, name+"=") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cachingBody stores the body once it has been read completely
+type cachingBody struct {
+	io.ReadCloser
+	store func(body []byte)
+
+	buffer    bytes.Buffer
+	oversized bool
+}
+
+func (c *cachingBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+
+	if !c.oversized {
+		c.buffer.Write(p[:n])
+
+		if c.buffer.Len() > maxCachedBodySize {
+			c.oversized = true
+			c.buffer = bytes.Buffer{}
+		}
+	}
+
+	if err == io.EOF && !c.oversized {
+		c.oversized = true // only store the body once
+		c.store(c.buffer.Bytes())
+	}
+
+	return n, err
+}
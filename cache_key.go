@@ -0,0 +1,145 @@
+package grequests
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// credentialHeaders are the request headers that identify the user making the request
+var credentialHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// CacheKeyOptions customizes how cached responses are keyed. By default a response is keyed
+// on the method, the (normalized) URL and a hash of the credentials (Authorization, Cookie and
+// Proxy-Authorization headers) of the request, and the headers named within the Vary header of
+// the response select between the variants stored under that key
+type CacheKeyOptions struct {
+	// IgnoreQueryParams are query parameters that don't change the response (e.g. tracking
+	// parameters) and are left out of the cache key so the response isn't needlessly duplicated
+	IgnoreQueryParams []string
+
+	// Principal (if set) returns the identity the request is made on behalf of (e.g. the user
+	// behind the Authorization header). It replaces the hash of the credentials within the key
+	// (e.g. so a refreshed token still matches). Responses are only shared between requests
+	// with the same principal
+	Principal func(req *http.Request) string
+
+	// KeyFunc (if set) replaces the default cache key entirely
+	KeyFunc func(req *http.Request) string
+}
+
+// Key returns the cache key of the request. A nil *CacheKeyOptions uses the defaults
+func (o *CacheKeyOptions) Key(req *http.Request) string {
+	if o != nil && o.KeyFunc != nil {
+		return o.KeyFunc(req)
+	}
+
+	var ignored []string
+
+	if o != nil {
+		ignored = o.IgnoreQueryParams
+	}
+
+	key := req.Method + " " + normalizeCacheURL(req.URL, ignored)
+
+	if o != nil && o.Principal != nil {
+		key += "\x00" + o.Principal(req)
+	} else if credentials := credentialsHash(req); credentials != "" {
+		// The response fetched with the credentials of one user must not be served to another
+		key += "\x00" + credentials
+	}
+
+	return key
+}
+
+// credentialsHash returns a hash of the credential headers of the request ("" if there are none)
+func credentialsHash(req *http.Request) string {
+	hash := sha256.New()
+	found := false
+
+	for _, name := range credentialHeaders {
+		for _, value := range req.Header.Values(name) {
+			found = true
+			hash.Write([]byte(name + ":" + value + "\n"))
+		}
+	}
+
+	if !found {
+		return ""
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// normalizeCacheURL returns the URL without its fragment and ignored query parameters, with
+// the remaining query parameters sorted so that their order doesn't change the key
+func normalizeCacheURL(u *url.URL, ignoredParams []string) string {
+	normalized := *u
+	normalized.Fragment = ""
+	normalized.RawFragment = ""
+	normalized.Host = strings.ToLower(normalized.Host)
+
+	query := normalized.Query()
+
+	for _, param := range ignoredParams {
+		query.Del(param)
+	}
+
+	// url.Values.Encode sorts by key
+	normalized.RawQuery = query.Encode()
+
+	return normalized.String()
+}
+
+// varyHeaderNames returns the (canonical and sorted) request headers named by the Vary header
+// of the response. The second return value is false when the response can't be cached as it
+// varies on `*`
+func varyHeaderNames(header http.Header) ([]string, bool) {
+	var names []string
+	seen := map[string]struct{}{}
+
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+
+			if name == "" {
+				continue
+			}
+
+			if name == "*" {
+				return nil, false
+			}
+
+			name = http.CanonicalHeaderKey(name)
+
+			if _, ok := seen[name]; ok {
+				continue
+			}
+
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, true
+}
+
+// varyKey returns the values of the named request headers, which select a stored variant of
+// a response. Two requests match the same variant when their varyKey is equal
+func varyKey(req *http.Request, names []string) string {
+	var key strings.Builder
+
+	for _, name := range names {
+		key.WriteString(name)
+		key.WriteByte(':')
+		key.WriteString(strings.Join(req.Header.Values(name), ","))
+		key.WriteByte('\n')
+	}
+
+	return key.String()
+}
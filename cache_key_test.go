@@ -0,0 +1,75 @@
+package grequests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCacheKeyNormalizesURL(t *testing.T) {
+	first, _ := http.NewRequest("GET", "http://Example.com/path?b=2&a=1&utm_source=x#top", nil)
+	second, _ := http.NewRequest("GET", "http://example.com/path?a=1&b=2", nil)
+
+	opts := &CacheKeyOptions{IgnoreQueryParams: []string{"utm_source"}}
+
+	if opts.Key(first) != opts.Key(second) {
+		t.Errorf("Equivalent requests have different keys: %q %q", opts.Key(first), opts.Key(second))
+	}
+
+	var defaults *CacheKeyOptions
+
+	if defaults.Key(first) == defaults.Key(second) {
+		t.Error("Query parameters were ignored without being configured")
+	}
+
+	post, _ := http.NewRequest("POST", "http://example.com/path?a=1&b=2", nil)
+
+	if opts.Key(post) == opts.Key(second) {
+		t.Error("Method is not part of the key")
+	}
+}
+
+func TestCacheKeyPrincipal(t *testing.T) {
+	opts := &CacheKeyOptions{Principal: func(req *http.Request) string {
+		return req.Header.Get("Authorization")
+	}}
+
+	alice, _ := http.NewRequest("GET", "http://example.com/me", nil)
+	alice.Header.Set("Authorization", "Bearer alice")
+	bob, _ := http.NewRequest("GET", "http://example.com/me", nil)
+	bob.Header.Set("Authorization", "Bearer bob")
+
+	if opts.Key(alice) == opts.Key(bob) {
+		t.Error("Responses would be shared between principals")
+	}
+
+	opts.KeyFunc = func(req *http.Request) string { return "fixed" }
+
+	if opts.Key(alice) != "fixed" {
+		t.Error("KeyFunc was not used")
+	}
+}
+
+func TestCacheKeyVary(t *testing.T) {
+	header := http.Header{}
+	header.Add("Vary", "accept-language, Accept")
+	header.Add("Vary", "Accept")
+
+	names, ok := varyHeaderNames(header)
+
+	if !ok || len(names) != 2 || names[0] != "Accept" || names[1] != "Accept-Language" {
+		t.Fatal("Vary header was not parsed: ", names, ok)
+	}
+
+	english, _ := http.NewRequest("GET", "http://example.com/", nil)
+	english.Header.Set("Accept-Language", "en")
+	french, _ := http.NewRequest("GET", "http://example.com/", nil)
+	french.Header.Set("Accept-Language", "fr")
+
+	if varyKey(english, names) == varyKey(french, names) {
+		t.Error("Variants were not separated")
+	}
+
+	if _, ok := varyHeaderNames(http.Header{"Vary": {"Accept, *"}}); ok {
+		t.Error("Vary: * should not be cacheable")
+	}
+}
@@ -0,0 +1,197 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	var hits, notModified int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("ETag", `"v1"`)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Write([]byte("cached body"))
+	}))
+	defer ts.Close()
+
+	session := NewSession(&RequestOptions{Cache: NewMemoryCache()})
+
+	for i := 0; i < 3; i++ {
+		resp, err := session.Get(ts.URL, nil)
+
+		if err != nil {
+			t.Fatal("Request failed: ", err)
+		}
+
+		if resp.String() != "cached body" || resp.StatusCode != http.StatusOK {
+			t.Error("Invalid response: ", resp.StatusCode, resp.String())
+		}
+
+		if fromCache := resp.Header.Get("X-From-Cache") == "1"; fromCache != (i > 0) {
+			t.Error("Unexpected X-From-Cache header on request ", i)
+		}
+	}
+
+	if hits != 3 || notModified != 2 {
+		t.Errorf("Expected 2 revalidations, got %d hits and %d not modified", hits, notModified)
+	}
+
+	session.CloseIdleConnections()
+}
+
+func TestCacheServesFreshResponses(t *testing.T) {
+	var hits int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte("fresh"))
+	}))
+	defer ts.Close()
+
+	ro := &RequestOptions{Cache: NewMemoryCache()}
+
+	for i := 0; i < 2; i++ {
+		resp, err := Get(ts.URL, ro)
+
+		if err != nil || resp.String() != "fresh" {
+			t.Fatal("Invalid response: ", resp.String(), err)
+		}
+	}
+
+	if hits != 1 {
+		t.Error("Fresh response was not served from the cache: ", hits)
+	}
+
+	// A successful POST invalidates the cached response
+	if _, err := Post(ts.URL, ro); err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if _, err := Get(ts.URL, ro); err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if hits != 3 {
+		t.Error("Cached response was not invalidated: ", hits)
+	}
+}
+
+func TestCacheRespectsVary(t *testing.T) {
+	var hits int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Language")
+		w.Write([]byte(r.Header.Get("Accept-Language")))
+	}))
+	defer ts.Close()
+
+	cache := NewMemoryCache()
+
+	for _, language := range []string{"en", "fr"} {
+		resp, err := Get(ts.URL, &RequestOptions{Cache: cache, Headers: map[string]string{"Accept-Language": language}})
+
+		if err != nil {
+			t.Fatal("Request failed: ", err)
+		}
+
+		if resp.String() != language {
+			t.Error("Response was shared across variants: ", resp.String())
+		}
+	}
+
+	if hits != 2 {
+		t.Error("Expected both variants to be requested: ", hits)
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir())
+
+	if err != nil {
+		t.Fatal("Unable to create cache: ", err)
+	}
+
+	stored := &CachedResponse{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Etag": {`"v1"`}},
+		Body:       []byte("body"),
+		StoredAt:   time.Now(),
+	}
+
+	cache.Set("key", stored)
+
+	cached, ok := cache.Get("key")
+
+	if !ok || string(cached.Body) != "body" || cached.Header.Get("ETag") != `"v1"` {
+		t.Fatal("Response was not stored: ", cached, ok)
+	}
+
+	cache.Delete("key")
+
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Response was not deleted")
+	}
+}
+
+func TestFreshnessLifetime(t *testing.T) {
+	if lifetime, ok := freshnessLifetime(http.Header{"Cache-Control": {"public, max-age=30"}}, time.Now()); !ok || lifetime != 30*time.Second {
+		t.Error("max-age was not parsed: ", lifetime, ok)
+	}
+
+	if _, ok := freshnessLifetime(http.Header{"Cache-Control": {"no-cache, max-age=30"}}, time.Now()); ok {
+		t.Error("no-cache response has a lifetime")
+	}
+
+	now := time.Now().UTC()
+
+	header := http.Header{
+		"Date":    {now.Format(http.TimeFormat)},
+		"Expires": {now.Add(time.Hour).Format(http.TimeFormat)},
+	}
+
+	if lifetime, ok := freshnessLifetime(header, now); !ok || lifetime != time.Hour {
+		t.Error("Expires was not parsed: ", lifetime, ok)
+	}
+}
+
+func TestCacheSeparatesCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer ts.Close()
+
+	session := NewSession(&RequestOptions{Cache: NewMemoryCache()})
+
+	for i, token := range []string{"alice", "bob", "alice"} {
+		resp, err := session.Get(ts.URL, &RequestOptions{BearerToken: token})
+
+		if err != nil {
+			t.Fatal("Request failed: ", err)
+		}
+
+		if resp.String() != "Bearer "+token {
+			t.Errorf("Response of another user was served to %s: %s", token, resp.String())
+		}
+
+		if fromCache := resp.Header.Get("X-From-Cache") == "1"; fromCache != (i == 2) {
+			t.Error("Unexpected X-From-Cache header on request ", i)
+		}
+	}
+
+	session.CloseIdleConnections()
+}
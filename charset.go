@@ -0,0 +1,68 @@
+package grequests
+
+import (
+	"bytes"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+)
+
+// textBody returns the internal buffer converted into UTF-8 (the conversion is only done once)
+func (r *Response) textBody() []byte {
+	if r.utf8Body != nil {
+		return r.utf8Body
+	}
+
+	body := r.internalByteBuffer.Bytes()
+
+	if r.disableCharsetDecoding {
+		return body
+	}
+
+	e := bodyEncoding(body, r.Header.Get("Content-Type"))
+
+	if e == nil {
+		return body
+	}
+
+	decoded, err := e.NewDecoder().Bytes(body)
+
+	// The body is returned as it was sent if it can't be converted
+	if err != nil {
+		return body
+	}
+
+	// The byte order mark is only needed to determine the encoding
+	r.utf8Body = bytes.TrimPrefix(decoded, []byte("\ufeff"))
+
+	return r.utf8Body
+}
+
+// bodyEncoding returns the encoding of a text body or nil if it doesn't need converting into UTF-8.
+// The meta tags of the body (and the windows-1252 fallback) are only trusted if the body isn't
+// valid UTF-8. XML bodies are never sniffed because the XML declaration names their encoding
+// (and converting them would make the declaration wrong)
+func bodyEncoding(body []byte, contentType string) encoding.Encoding {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	if !isTextMediaType(mediaType) {
+		return nil
+	}
+
+	e, name, certain := charset.DetermineEncoding(body, contentType)
+
+	if name == "utf-8" || (!certain && (isXMLMediaType(mediaType) || utf8.Valid(body))) {
+		return nil
+	}
+
+	return e
+}
+
+// isTextMediaType reports if the media type is text (whose charset may be converted)
+func isTextMediaType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") || isJSONMediaType(mediaType) || isXMLMediaType(mediaType) ||
+		mediaType == "application/javascript"
+}
@@ -0,0 +1,68 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseCharsetDecoding(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"latin1 header", "text/plain; charset=ISO-8859-1", "caf\xe9", "café"},
+		{"shift_jis header", "text/html; charset=Shift_JIS", "\x93\xfa\x96\x7b", "日本"},
+		{"gbk meta tag", "text/html", `<meta charset="gbk"><p>` + "\xd6\xd0\xce\xc4", `<meta charset="gbk"><p>中文`},
+		{"utf-16 bom", "text/plain", "\xff\xfeh\x00i\x00", "hi"},
+		{"utf-8 without charset", "text/html", "café", "café"},
+		{"binary", "application/octet-stream", "caf\xe9", "caf\xe9"},
+		{"xml declaration", "application/xml", `<?xml version="1.0" encoding="ISO-8859-1"?><a>` + "\xe9</a>",
+			`<?xml version="1.0" encoding="ISO-8859-1"?><a>` + "\xe9</a>"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", test.contentType)
+				w.Write([]byte(test.body))
+			}))
+			defer ts.Close()
+
+			resp, err := Get(ts.URL, nil)
+
+			if err != nil {
+				t.Fatal("Unable to make request: ", err)
+			}
+
+			if got := resp.String(); got != test.want {
+				t.Errorf("String() = %q, want %q", got, test.want)
+			}
+
+			// Bytes is never converted
+			if got := string(resp.Bytes()); got != test.body {
+				t.Errorf("Bytes() = %q, want %q", got, test.body)
+			}
+		})
+	}
+}
+
+func TestDisableCharsetDecoding(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=ISO-8859-1")
+		w.Write([]byte("caf\xe9"))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, &RequestOptions{DisableCharsetDecoding: true})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if got := resp.String(); got != "caf\xe9" {
+		t.Errorf("Body was converted: %q", got)
+	}
+}
@@ -0,0 +1,206 @@
+package grequests
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ChecksumError is the error returned when the body of the response doesn't match its expected
+// checksum. It matches ErrChecksumMismatch
+type ChecksumError struct {
+	// Algorithm is the algorithm of the checksum ("md5", "sha256" or "sha512")
+	Algorithm string
+
+	// Source is where the expected checksum came from e.g. "ExpectedSHA256" or "Content-MD5"
+	Source string
+
+	// Expected and Actual are the hex encoded checksums
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("grequests: The %s checksum of the response body is %s but %s is %s",
+		e.Algorithm, e.Actual, e.Source, e.Expected)
+}
+
+// Is allows errors.Is(err, ErrChecksumMismatch)
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// checksum is a checksum that the body of the response is expected to match
+type checksum struct {
+	algorithm string
+	source    string
+	expected  []byte
+	hash      hash.Hash
+}
+
+// checksumAlgorithms are the algorithms that checksums may use (keyed by their name within a Digest header)
+var checksumAlgorithms = map[string]struct {
+	name    string
+	newHash func() hash.Hash
+}{
+	"md5":     {"md5", md5.New},
+	"sha-256": {"sha256", sha256.New},
+	"sha-512": {"sha512", sha512.New},
+}
+
+// verifyResponseChecksum verifies the body of the response against ExpectedMD5, ExpectedSHA256
+// and (if VerifyDigestHeaders is set) the digest headers as the body is read
+func verifyResponseChecksum(r *Response, ro *RequestOptions) error {
+	if ro.ExpectedMD5 == "" && ro.ExpectedSHA256 == "" && !ro.VerifyDigestHeaders {
+		return nil
+	}
+
+	if !r.Ok || r.StatusCode == http.StatusPartialContent {
+		return nil
+	}
+
+	checksums, err := expectedChecksums(ro)
+
+	if err != nil {
+		return err
+	}
+
+	// The digests of the headers are of the body that was sent (before it was decompressed)
+	if ro.VerifyDigestHeaders && r.encodedCounter == nil && !r.RawResponse.Uncompressed {
+		checksums = append(checksums, digestHeaderChecksums(r.Header)...)
+	}
+
+	if len(checksums) == 0 {
+		return nil
+	}
+
+	r.RawResponse.Body = &checksumVerifier{ReadCloser: r.RawResponse.Body, checksums: checksums}
+
+	return nil
+}
+
+// expectedChecksums returns the checksums of ExpectedMD5 and ExpectedSHA256
+func expectedChecksums(ro *RequestOptions) ([]*checksum, error) {
+	var checksums []*checksum
+
+	for _, expected := range []struct{ source, algorithm, value string }{
+		{"ExpectedMD5", "md5", ro.ExpectedMD5},
+		{"ExpectedSHA256", "sha-256", ro.ExpectedSHA256},
+	} {
+		if expected.value == "" {
+			continue
+		}
+
+		value, err := hex.DecodeString(expected.value)
+
+		if err != nil {
+			return nil, fmt.Errorf("grequests: %s is not hex encoded: %w", expected.source, err)
+		}
+
+		algorithm := checksumAlgorithms[expected.algorithm]
+
+		checksums = append(checksums, &checksum{
+			algorithm: algorithm.name,
+			source:    expected.source,
+			expected:  value,
+			hash:      algorithm.newHash(),
+		})
+	}
+
+	return checksums, nil
+}
+
+// digestHeaderChecksums returns the checksums of the Digest (RFC 3230), Content-Digest (RFC 9530)
+// and Content-MD5 headers. Unknown algorithms and malformed digests are ignored
+func digestHeaderChecksums(header http.Header) []*checksum {
+	var checksums []*checksum
+
+	add := func(source, algorithmName, encoded string) {
+		algorithm, ok := checksumAlgorithms[strings.ToLower(strings.TrimSpace(algorithmName))]
+
+		if !ok {
+			return
+		}
+
+		// Content-Digest encodes the digest as a structured field byte sequence (:base64:)
+		value, err := base64.StdEncoding.DecodeString(strings.Trim(strings.TrimSpace(encoded), ":"))
+
+		if err != nil {
+			return
+		}
+
+		checksums = append(checksums, &checksum{
+			algorithm: algorithm.name,
+			source:    source,
+			expected:  value,
+			hash:      algorithm.newHash(),
+		})
+	}
+
+	for _, source := range []string{"Digest", "Content-Digest"} {
+		for _, headerValue := range header.Values(source) {
+			for _, digest := range strings.Split(headerValue, ",") {
+				if algorithm, value, ok := strings.Cut(digest, "="); ok {
+					add(source, algorithm, value)
+				}
+			}
+		}
+	}
+
+	if contentMD5 := header.Get("Content-MD5"); contentMD5 != "" {
+		add("Content-MD5", "md5", contentMD5)
+	}
+
+	return checksums
+}
+
+// checksumVerifier hashes the body as it is read and verifies the checksums once the whole body has been read
+type checksumVerifier struct {
+	io.ReadCloser
+	checksums []*checksum
+	err       error
+}
+
+func (c *checksumVerifier) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	n, err := c.ReadCloser.Read(p)
+
+	for _, checksum := range c.checksums {
+		checksum.hash.Write(p[:n])
+	}
+
+	if err == io.EOF {
+		if c.err = c.verify(); c.err != nil {
+			return n, c.err
+		}
+	}
+
+	return n, err
+}
+
+// verify returns a *ChecksumError for the first checksum that doesn't match
+func (c *checksumVerifier) verify() error {
+	for _, checksum := range c.checksums {
+		if actual := checksum.hash.Sum(nil); !bytes.Equal(actual, checksum.expected) {
+			return &ChecksumError{
+				Algorithm: checksum.algorithm,
+				Source:    checksum.source,
+				Expected:  hex.EncodeToString(checksum.expected),
+				Actual:    hex.EncodeToString(actual),
+			}
+		}
+	}
+
+	return nil
+}
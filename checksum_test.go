@@ -0,0 +1,125 @@
+package grequests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+// The checksums of "Hello World"
+const (
+	helloWorldMD5    = "b10a8db164e0754105b7a99be72e3fe5"
+	helloWorldSHA256 = "a591a6d40bf420404a011733cfb7b190d62c65bf0bcda32b57b277d9ad9f146e"
+)
+
+func TestExpectedChecksums(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Hello World"))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, &RequestOptions{ExpectedMD5: helloWorldMD5, ExpectedSHA256: helloWorldSHA256})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if resp.String() != "Hello World" || resp.Error != nil {
+		t.Error("Matching checksums failed: ", resp.Error)
+	}
+
+	resp, err = Get(ts.URL, &RequestOptions{ExpectedSHA256: helloWorldMD5 + helloWorldMD5})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	err = resp.DownloadToFile(filepath.Join(t.TempDir(), "hello"))
+
+	var checksumErr *ChecksumError
+
+	if !errors.Is(err, ErrChecksumMismatch) || !errors.As(err, &checksumErr) {
+		t.Fatal("Mismatched checksum was not returned: ", err)
+	}
+
+	if checksumErr.Algorithm != "sha256" || checksumErr.Source != "ExpectedSHA256" || checksumErr.Actual != helloWorldSHA256 {
+		t.Errorf("Unexpected checksum error: %+v", checksumErr)
+	}
+
+	if _, err := Get(ts.URL, &RequestOptions{ExpectedMD5: "not hex"}); err == nil {
+		t.Error("Invalid checksum was accepted")
+	}
+}
+
+func TestVerifyDigestHeaders(t *testing.T) {
+	tests := []struct {
+		header string
+		value  string
+		match  bool
+	}{
+		{"Digest", "SHA-256=pZGm1Av0IEBKARczz7exkNYsZb8LzaMrV7J32a2fFG4=", true},
+		{"Digest", "UNIXsum=30637, SHA-256=AAAA1Av0IEBKARczz7exkNYsZb8LzaMrV7J32a2fFG4=", false},
+		{"Content-Digest", "sha-256=:pZGm1Av0IEBKARczz7exkNYsZb8LzaMrV7J32a2fFG4=:", true},
+		{"Content-MD5", "sQqNsWTgdUEFt6mb5y4/5Q==", true},
+		{"Content-MD5", "AAAAsWTgdUEFt6mb5y4/5Q==", false},
+	}
+
+	for _, test := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(test.header, test.value)
+			w.Write([]byte("Hello World"))
+		}))
+
+		resp, err := Get(ts.URL, &RequestOptions{VerifyDigestHeaders: true})
+
+		if err != nil {
+			t.Fatal("Unable to make request: ", err)
+		}
+
+		if body := resp.String(); body != "Hello World" {
+			t.Error("Verifying changed the body: ", body)
+		}
+
+		if matched := resp.Error == nil; matched != test.match {
+			t.Errorf("%s: %s verified as %v: %v", test.header, test.value, matched, resp.Error)
+		}
+
+		ts.Close()
+	}
+}
+
+func TestInvalidChecksumIsNotSent(t *testing.T) {
+	var requests int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer ts.Close()
+
+	if _, err := Post(ts.URL, &RequestOptions{ExpectedSHA256: "not hex", JSON: map[string]string{"a": "b"}}); err == nil {
+		t.Error("Invalid checksum was accepted")
+	}
+
+	if requests != 0 {
+		t.Error("Request with an invalid checksum was sent")
+	}
+}
+
+func TestRejectedResponseKeepsStatusCode(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Hello World"))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, &RequestOptions{MaxResponseBodySize: 1})
+
+	if !errors.Is(err, ErrResponseBodyTooLarge) {
+		t.Fatal("Expected ErrResponseBodyTooLarge, got: ", err)
+	}
+
+	if len(resp.RetryHistory) != 1 || resp.RetryHistory[0].StatusCode != http.StatusOK {
+		t.Error("Status code of the rejected response was not recorded: ", resp.RetryHistory)
+	}
+}
@@ -0,0 +1,211 @@
+package grequests
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// Default value for CircuitBreaker FailureThreshold
+	defaultCircuitFailureThreshold = 5
+
+	// Default value for CircuitBreaker OpenTimeout
+	defaultCircuitOpenTimeout = 30 * time.Second
+)
+
+// ErrCircuitOpen is the error (wrapped by *CircuitOpenError) returned when a request is
+// short-circuited because the circuit breaker of the host is open
+var ErrCircuitOpen = errors.New("grequests: Circuit breaker is open")
+
+// CircuitOpenError is the error returned when a request is short-circuited by a CircuitBreaker
+type CircuitOpenError struct {
+	// Host is the host whose circuit is open
+	Host string
+
+	// RetryAt is when the circuit will allow a probe request through
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s for %s (retry at %s)", ErrCircuitOpen, e.Host, e.RetryAt.Format(time.RFC3339))
+}
+
+// Unwrap allows errors.Is(err, ErrCircuitOpen)
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// CircuitState is the state of the circuit of a host
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen short-circuits every request
+	CircuitOpen
+
+	// CircuitHalfOpen lets a single probe request through. If the probe succeeds the
+	// circuit is closed, otherwise it is opened again
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return "closed"
+}
+
+// CircuitBreaker tracks the failures of each host and short-circuits requests to a host (returning a
+// *CircuitOpenError) once FailureThreshold consecutive requests have failed. After OpenTimeout a single
+// probe request is let through to check if the host has recovered. It is safe to share between sessions
+type CircuitBreaker struct {
+	// FailureThreshold is the amount of consecutive failures that open the circuit. The default is 5
+	FailureThreshold int
+
+	// OpenTimeout is how long the circuit stays open before a probe is let through. The default is 30 seconds
+	OpenTimeout time.Duration
+
+	// IsFailure (if set) decides if the outcome of a request is a failure. By default errors and
+	// 5xx responses are failures
+	IsFailure func(resp *Response, err error) bool
+
+	// OnStateChange (if set) is called when the circuit of a host changes state
+	OnStateChange func(host string, from, to CircuitState)
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is the state of a single host
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker returns a CircuitBreaker that opens after threshold consecutive failures and
+// probes the host again after openTimeout
+func NewCircuitBreaker(threshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{FailureThreshold: threshold, OpenTimeout: openTimeout}
+}
+
+func (cb *CircuitBreaker) failureThreshold() int {
+	if cb.FailureThreshold <= 0 {
+		return defaultCircuitFailureThreshold
+	}
+
+	return cb.FailureThreshold
+}
+
+func (cb *CircuitBreaker) openTimeout() time.Duration {
+	if cb.OpenTimeout <= 0 {
+		return defaultCircuitOpenTimeout
+	}
+
+	return cb.OpenTimeout
+}
+
+// State returns the state of the circuit of the host
+func (cb *CircuitBreaker) State(host string) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.circuits[host]
+
+	if !ok {
+		return CircuitClosed
+	}
+
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= cb.openTimeout() {
+		return CircuitHalfOpen
+	}
+
+	return c.state
+}
+
+// allow returns an error if the request to the host must be short-circuited. Once the
+// circuit has been open for OpenTimeout a single probe request is allowed
+func (cb *CircuitBreaker) allow(host string) error {
+	cb.mu.Lock()
+
+	c, ok := cb.circuits[host]
+
+	if !ok || c.state == CircuitClosed {
+		cb.mu.Unlock()
+		return nil
+	}
+
+	retryAt := c.openedAt.Add(cb.openTimeout())
+
+	if c.state == CircuitOpen && !time.Now().Before(retryAt) {
+		c.state = CircuitHalfOpen
+		cb.mu.Unlock()
+
+		cb.stateChanged(host, CircuitOpen, CircuitHalfOpen)
+		return nil
+	}
+
+	cb.mu.Unlock()
+
+	return &CircuitOpenError{Host: host, RetryAt: retryAt}
+}
+
+// record records the outcome of a request to the host
+func (cb *CircuitBreaker) record(host string, resp *Response, err error) {
+	failed := err != nil || resp.StatusCode >= 500
+
+	if cb.IsFailure != nil {
+		failed = cb.IsFailure(resp, err)
+	}
+
+	cb.mu.Lock()
+
+	if cb.circuits == nil {
+		cb.circuits = map[string]*circuit{}
+	}
+
+	c, ok := cb.circuits[host]
+
+	if !ok {
+		c = &circuit{}
+		cb.circuits[host] = c
+	}
+
+	from := c.state
+
+	switch {
+	case !failed:
+		c.failures = 0
+		c.state = CircuitClosed
+	case c.state == CircuitOpen:
+		// A request that was sent before the circuit opened
+	default:
+		c.failures++
+
+		if c.state == CircuitHalfOpen || c.failures >= cb.failureThreshold() {
+			c.state = CircuitOpen
+			c.openedAt = time.Now()
+		}
+	}
+
+	to := c.state
+
+	cb.mu.Unlock()
+
+	cb.stateChanged(host, from, to)
+}
+
+// stateChanged calls OnStateChange if the state of the circuit changed
+func (cb *CircuitBreaker) stateChanged(host string, from, to CircuitState) {
+	if from != to && cb.OnStateChange != nil {
+		cb.OnStateChange(host, from, to)
+	}
+}
@@ -0,0 +1,104 @@
+package grequests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAndProbes(t *testing.T) {
+	var hits int32
+	var healthy int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	var transitions []string
+
+	session := NewSession(nil)
+	session.CircuitBreaker = NewCircuitBreaker(2, 50*time.Millisecond)
+	session.CircuitBreaker.OnStateChange = func(host string, from, to CircuitState) {
+		transitions = append(transitions, from.String()+"->"+to.String())
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := session.Get(ts.URL, nil); err != nil {
+			t.Fatal("Request failed: ", err)
+		}
+	}
+
+	_, err := session.Get(ts.URL, nil)
+
+	var openErr *CircuitOpenError
+
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("Expected the circuit to be open, got: ", err)
+	}
+
+	if hits != 2 {
+		t.Error("Short-circuited request was sent: ", hits)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	atomic.StoreInt32(&healthy, 1)
+
+	if resp, err := session.Get(ts.URL, nil); err != nil || !resp.Ok {
+		t.Fatal("Probe request failed: ", err)
+	}
+
+	if state := session.CircuitBreaker.State(openErr.Host); state != CircuitClosed {
+		t.Error("Circuit was not closed after a successful probe: ", state)
+	}
+
+	expected := []string{"closed->open", "open->half-open", "half-open->closed"}
+
+	if len(transitions) != len(expected) {
+		t.Fatal("Unexpected transitions: ", transitions)
+	}
+
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Fatal("Unexpected transitions: ", transitions)
+		}
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	cb := NewCircuitBreaker(1, 10*time.Millisecond)
+
+	cb.record("example.com", &Response{}, errors.New("connection refused"))
+
+	if cb.State("example.com") != CircuitOpen {
+		t.Fatal("Circuit was not opened")
+	}
+
+	time.Sleep(15 * time.Millisecond)
+
+	if err := cb.allow("example.com"); err != nil {
+		t.Fatal("Probe was not allowed: ", err)
+	}
+
+	if err := cb.allow("example.com"); err == nil {
+		t.Error("A second probe was allowed")
+	}
+
+	cb.record("example.com", &Response{StatusCode: 500}, nil)
+
+	if cb.State("example.com") != CircuitOpen {
+		t.Error("Circuit was not reopened after a failed probe")
+	}
+
+	if err := cb.allow("other.example.com"); err != nil {
+		t.Error("Another host was short-circuited: ", err)
+	}
+}
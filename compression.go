@@ -0,0 +1,186 @@
+package grequests
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"sync"
+)
+
+// compressibleContentTypes are the request bodies that we will compress once they exceed the CompressThreshold
+var compressibleContentTypes = map[string]struct{}{
+	"application/json":                  {},
+	"application/x-www-form-urlencoded": {},
+}
+
+// maxBufferedCompressionSize is the largest body that is compressed in memory. Larger bodies
+// (e.g. files) are compressed as they are sent
+const maxBufferedCompressionSize = 1 << 20
+
+// compressRequestBody will compress the body of the request if CompressRequestBody is set or
+// the body exceeds the CompressThreshold
+func compressRequestBody(ro *RequestOptions, req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0 || req.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+
+	if ro.CompressRequestBody {
+		return encodeRequestBody(req, ro.RequestBodyEncoding)
+	}
+
+	if ro.CompressThreshold <= 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	if err != nil {
+		return nil
+	}
+
+	if _, ok := compressibleContentTypes[mediaType]; !ok {
+		return nil
+	}
+
+	// The body builders always know how large the body is – if we don't, leave the body alone
+	if req.ContentLength <= ro.CompressThreshold {
+		return nil
+	}
+
+	return encodeRequestBody(req, ro.RequestBodyEncoding)
+}
+
+// newBodyEncoder returns a writer that compresses into w using the content encoding
+func newBodyEncoder(w io.Writer, encoding string) (io.WriteCloser, error) {
+	switch encoding {
+	case "", "gzip":
+		return gzip.NewWriter(w), nil
+	case "deflate":
+		// The deflate content coding is the zlib format (RFC 9110)
+		return zlib.NewWriter(w), nil
+	default:
+		return nil, fmt.Errorf("grequests: Unsupported request body encoding %q", encoding)
+	}
+}
+
+// encodeRequestBody replaces the body of the request with a compressed version of it. Small bodies
+// that can be read again are compressed in memory (so the compressed length is known), other bodies
+// are compressed as they are sent. Bodies that can be read again remain so (each copy is compressed
+// from a fresh reader of the original body)
+func encodeRequestBody(req *http.Request, encoding string) error {
+	if encoding == "" {
+		encoding = "gzip"
+	}
+
+	if _, err := newBodyEncoder(ioutil.Discard, encoding); err != nil {
+		return err
+	}
+
+	if req.GetBody == nil {
+		streamRequestBody(req, encoding)
+		return nil
+	}
+
+	if req.ContentLength < 0 || req.ContentLength > maxBufferedCompressionSize {
+		getBody := req.GetBody
+
+		streamRequestBody(req, encoding)
+
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return &encodedStream{body: body, encoding: encoding}, nil
+		}
+
+		return nil
+	}
+
+	defer req.Body.Close()
+
+	compressedBody := &bytes.Buffer{}
+
+	encoder, _ := newBodyEncoder(compressedBody, encoding)
+
+	if _, err := io.Copy(encoder, req.Body); err != nil {
+		return err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	compressedBytes := compressedBody.Bytes()
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(compressedBytes))
+	req.ContentLength = int64(len(compressedBytes))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(compressedBytes)), nil
+	}
+	req.Header.Set("Content-Encoding", encoding)
+
+	return nil
+}
+
+// streamRequestBody compresses the body of the request as it is sent (the length of the
+// compressed body isn't known so it is sent chunked)
+func streamRequestBody(req *http.Request, encoding string) {
+	req.Body = &encodedStream{body: req.Body, encoding: encoding}
+	req.ContentLength = -1
+	req.Header.Set("Content-Encoding", encoding)
+}
+
+// encodedStream is a compressed request body. The body is compressed into a pipe (by a
+// separate goroutine) once the transport starts to read it
+type encodedStream struct {
+	body     io.ReadCloser
+	encoding string
+
+	once       sync.Once
+	pipeReader *io.PipeReader
+}
+
+func (e *encodedStream) start() {
+	pipeReader, pipeWriter := io.Pipe()
+	e.pipeReader = pipeReader
+
+	go func() {
+		pipeWriter.CloseWithError(e.write(pipeWriter))
+	}()
+}
+
+// write compresses the body into w and closes the body
+func (e *encodedStream) write(w io.Writer) error {
+	defer e.body.Close()
+
+	encoder, err := newBodyEncoder(w, e.encoding)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(encoder, e.body); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func (e *encodedStream) Read(p []byte) (int, error) {
+	e.once.Do(e.start)
+	return e.pipeReader.Read(p)
+}
+
+// Close stops the body from being compressed (which closes the body)
+func (e *encodedStream) Close() error {
+	e.once.Do(e.start)
+	return e.pipeReader.Close()
+}
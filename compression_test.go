@@ -0,0 +1,232 @@
+package grequests
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCompressThresholdLargeBody(t *testing.T) {
+	ro := &RequestOptions{
+		JSON:              map[string]string{"One": strings.Repeat("Two", 100)},
+		CompressThreshold: 100,
+	}
+
+	req, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro)
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	if err := compressRequestBody(ro, req); err != nil {
+		t.Fatal("Unable to compress request: ", err)
+	}
+
+	if req.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatal("Large body was not compressed")
+	}
+
+	gzipReader, err := gzip.NewReader(req.Body)
+
+	if err != nil {
+		t.Fatal("Body is not gzipped: ", err)
+	}
+
+	b, _ := ioutil.ReadAll(gzipReader)
+
+	if !strings.Contains(string(b), strings.Repeat("Two", 100)) {
+		t.Error("Compressed body is invalid: ", string(b))
+	}
+}
+
+func TestCompressThresholdSmallBody(t *testing.T) {
+	ro := &RequestOptions{
+		Data:              map[string]string{"One": "Two"},
+		CompressThreshold: 100,
+	}
+
+	req, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro)
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	if err := compressRequestBody(ro, req); err != nil {
+		t.Fatal("Unable to compress request: ", err)
+	}
+
+	if req.Header.Get("Content-Encoding") != "" {
+		t.Error("Small body was compressed")
+	}
+}
+
+type compressedXML struct {
+	A string
+}
+
+func TestCompressRequestBody(t *testing.T) {
+	var received []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reader io.Reader
+		var err error
+
+		switch r.Header.Get("Content-Encoding") {
+		case "gzip":
+			reader, err = gzip.NewReader(r.Body)
+		case "deflate":
+			reader, err = zlib.NewReader(r.Body)
+		default:
+			reader = r.Body
+		}
+
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(reader)
+		received = append(received, r.Header.Get("Content-Encoding")+" "+r.Header.Get("Content-Type")+" "+string(body))
+	}))
+	defer ts.Close()
+
+	requests := []*RequestOptions{
+		{XML: compressedXML{A: "b"}, CompressRequestBody: true},
+		{RequestBody: ioutil.NopCloser(strings.NewReader("raw")), ContentType: "text/plain", CompressRequestBody: true},
+		{Data: map[string]string{"a": "b"}, CompressRequestBody: true, RequestBodyEncoding: "deflate"},
+		{JSON: map[string]string{"a": "b"}, CompressRequestBody: true, Headers: map[string]string{"Content-Encoding": "identity"}},
+	}
+
+	for _, ro := range requests {
+		resp, err := Post(ts.URL, ro)
+
+		if err != nil || !resp.Ok {
+			t.Fatal("Request failed: ", err)
+		}
+	}
+
+	expected := []string{
+		"gzip application/xml <compressedXML><A>b</A></compressedXML>",
+		"gzip text/plain raw",
+		"deflate application/x-www-form-urlencoded a=b",
+		"identity application/json {\"a\":\"b\"}\n",
+	}
+
+	for i := range expected {
+		if i >= len(received) || received[i] != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], received)
+		}
+	}
+
+	if _, err := Post(ts.URL, &RequestOptions{Data: map[string]string{"a": "b"}, CompressRequestBody: true, RequestBodyEncoding: "br"}); err == nil {
+		t.Error("An unsupported encoding was accepted")
+	}
+}
+
+func TestCompressLargeFileBody(t *testing.T) {
+	contents := strings.Repeat("0123456789", maxBufferedCompressionSize/10+1)
+
+	fd, err := ioutil.TempFile("", "grequests-compress")
+
+	if err != nil {
+		t.Fatal("Unable to create temp file: ", err)
+	}
+	defer os.Remove(fd.Name())
+	defer fd.Close()
+
+	if _, err := fd.WriteString(contents); err != nil {
+		t.Fatal("Unable to write temp file: ", err)
+	}
+
+	if _, err := fd.Seek(0, io.SeekStart); err != nil {
+		t.Fatal("Unable to seek temp file: ", err)
+	}
+
+	var attempts []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gzipReader, err := gzip.NewReader(r.Body)
+
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(gzipReader)
+		attempts = append(attempts, strconv.FormatInt(r.ContentLength, 10))
+
+		if string(body) != contents {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// Fail the first attempt so the body has to be compressed again
+		if len(attempts) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	resp, err := Put(ts.URL, &RequestOptions{
+		RequestBody:         fd,
+		CompressRequestBody: true,
+		RetryPolicy:         &BackoffRetryPolicy{Backoff: ConstantBackoff(0)},
+	})
+
+	if err != nil || !resp.Ok {
+		t.Fatal("Request failed: ", err, resp.StatusCode)
+	}
+
+	if len(attempts) != 2 || attempts[0] != "-1" || attempts[1] != "-1" {
+		t.Error("Large body was not streamed on every attempt: ", attempts)
+	}
+}
+
+func TestCompressUploadProgress(t *testing.T) {
+	var received int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received = len(body)
+	}))
+	defer ts.Close()
+
+	var calls int
+	var transferred, total int64
+
+	ro := &RequestOptions{
+		JSON:                map[string]string{"One": strings.Repeat("Two", 1000)},
+		CompressRequestBody: true,
+		UploadProgress: func(sent, size int64) {
+			calls++
+			transferred, total = sent, size
+		},
+	}
+
+	req, err := prepareRequest("POST", ts.URL, ro)
+
+	if err != nil {
+		t.Fatal("Unable to prepare request: ", err)
+	}
+
+	req.Body.Close()
+
+	if calls != 0 {
+		t.Fatal("Progress was reported before the request was sent")
+	}
+
+	if _, err := Post(ts.URL, ro); err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if calls == 0 || transferred != int64(received) || total != int64(received) {
+		t.Errorf("Progress was not reported for the compressed body: %d of %d bytes (%d sent)", transferred, total, received)
+	}
+}
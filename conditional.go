@@ -0,0 +1,53 @@
+package grequests
+
+import (
+	"net/http"
+	"time"
+)
+
+// ETag returns the ETag header of the response (or an empty string if the server didn't send one)
+func (r *Response) ETag() string {
+	if r.Error != nil {
+		return ""
+	}
+
+	return r.Header.Get("ETag")
+}
+
+// LastModified returns the time of the Last-Modified header of the response. The zero time is
+// returned if the server didn't send the header (or it couldn't be parsed)
+func (r *Response) LastModified() time.Time {
+	if r.Error != nil {
+		return time.Time{}
+	}
+
+	lastModified, err := http.ParseTime(r.Header.Get("Last-Modified"))
+
+	if err != nil {
+		return time.Time{}
+	}
+
+	return lastModified
+}
+
+// NotModified reports if the server responded with 304 Not Modified (the resource hasn't changed
+// since the response passed as the Conditional option)
+func (r *Response) NotModified() bool {
+	return r.Error == nil && r.StatusCode == http.StatusNotModified
+}
+
+// addConditionalHeaders sends the validators of the Conditional response (unless the user set
+// the conditional headers themselves)
+func addConditionalHeaders(ro *RequestOptions, req *http.Request) {
+	if ro.Conditional == nil || ro.Conditional.Error != nil {
+		return
+	}
+
+	if etag := ro.Conditional.ETag(); etag != "" && req.Header.Get("If-None-Match") == "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	if lastModified := ro.Conditional.Header.Get("Last-Modified"); lastModified != "" && req.Header.Get("If-Modified-Since") == "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+}
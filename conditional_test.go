@@ -0,0 +1,62 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConditional(t *testing.T) {
+	lastModified := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	version := `"v1"`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+
+		if r.Header.Get("If-None-Match") == version {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", version)
+		w.Write([]byte("body"))
+	}))
+	defer ts.Close()
+
+	first, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if first.ETag() != version || !first.LastModified().Equal(lastModified) || first.NotModified() {
+		t.Errorf("Unexpected validators: %q %v", first.ETag(), first.LastModified())
+	}
+
+	second, err := Get(ts.URL, &RequestOptions{Conditional: first, RaiseForStatus: true})
+
+	if err != nil {
+		t.Fatal("304 was treated as an error: ", err)
+	}
+
+	if !second.NotModified() || second.Ok {
+		t.Error("Response was not a 304: ", second.StatusCode)
+	}
+
+	if second.RawResponse.Request.Header.Get("If-Modified-Since") != lastModified.Format(http.TimeFormat) {
+		t.Error("If-Modified-Since was not sent: ", second.RawResponse.Request.Header)
+	}
+
+	version = `"v2"`
+
+	third, err := Get(ts.URL, &RequestOptions{Conditional: first})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if third.NotModified() || third.ETag() != `"v2"` || third.String() != "body" {
+		t.Error("Changed resource was not returned: ", third.StatusCode)
+	}
+}
@@ -0,0 +1,268 @@
+package grequests
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// ErrNoCookieJar is the error returned when the cookies of a session without a cookie jar are accessed
+var ErrNoCookieJar = errors.New("grequests: Session does not have a cookie jar")
+
+// Cookies returns the cookies the session would send to the URL
+func (s *Session) Cookies(rawURL string) ([]*http.Cookie, error) {
+	if s.HTTPClient.Jar == nil {
+		return nil, ErrNoCookieJar
+	}
+
+	u, err := s.parseURL(rawURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.HTTPClient.Jar.Cookies(u), nil
+}
+
+// SetCookies stores the cookies within the cookie jar of the session as if they were set by the URL
+func (s *Session) SetCookies(rawURL string, cookies []*http.Cookie) error {
+	if s.HTTPClient.Jar == nil {
+		return ErrNoCookieJar
+	}
+
+	u, err := s.parseURL(rawURL)
+
+	if err != nil {
+		return err
+	}
+
+	s.HTTPClient.Jar.SetCookies(u, cookies)
+
+	return nil
+}
+
+// parseURL parses the URL (resolving it against the BaseURL of the session)
+func (s *Session) parseURL(rawURL string) (*url.URL, error) {
+	resolved, err := s.resolveURL(rawURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return url.Parse(resolved)
+}
+
+// PersistentJar is a cookie jar that can be saved to (and is loaded from) a JSON file so the
+// cookies of a session survive restarts. Use it as the CookieJar of the RequestOptions of a Session
+// and call Save before exiting. A PersistentJar is safe for concurrent use
+type PersistentJar struct {
+	fileName string
+
+	mu      sync.Mutex
+	jar     *cookiejar.Jar
+	cookies map[string]persistedCookie
+}
+
+// persistedCookie is a cookie along with the URL that set it
+type persistedCookie struct {
+	URL      string        `json:"url"`
+	Name     string        `json:"name"`
+	Value    string        `json:"value"`
+	Domain   string        `json:"domain,omitempty"`
+	Path     string        `json:"path,omitempty"`
+	Expires  time.Time     `json:"expires"`
+	Secure   bool          `json:"secure,omitempty"`
+	HTTPOnly bool          `json:"httpOnly,omitempty"`
+	SameSite http.SameSite `json:"sameSite,omitempty"`
+}
+
+// NewPersistentJar returns a cookie jar that is saved to the file. The cookies within the file
+// (if it exists) are loaded into the jar
+func NewPersistentJar(fileName string) (*PersistentJar, error) {
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+
+	j := &PersistentJar{fileName: fileName, jar: jar, cookies: map[string]persistedCookie{}}
+
+	contents, err := ioutil.ReadFile(fileName)
+
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var persisted []persistedCookie
+
+	if err := json.Unmarshal(contents, &persisted); err != nil {
+		return nil, err
+	}
+
+	for _, p := range persisted {
+		u, err := url.Parse(p.URL)
+
+		if err != nil {
+			continue
+		}
+
+		j.SetCookies(u, []*http.Cookie{p.cookie()})
+	}
+
+	return j, nil
+}
+
+// SetCookies implements http.CookieJar
+func (j *PersistentJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.jar.SetCookies(u, cookies)
+
+	now := time.Now()
+
+	for _, c := range cookies {
+		p := persistedCookie{
+			URL:      (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String(),
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  c.Expires,
+			Secure:   c.Secure,
+			HTTPOnly: c.HttpOnly,
+			SameSite: c.SameSite,
+		}
+
+		// Max-Age is relative to now so we store the time the cookie expires
+		if c.MaxAge > 0 {
+			p.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		}
+
+		key := persistedCookieKey(u, c)
+
+		if c.MaxAge < 0 || (!p.Expires.IsZero() && !p.Expires.After(now)) {
+			delete(j.cookies, key)
+			continue
+		}
+
+		j.cookies[key] = p
+	}
+}
+
+// Cookies implements http.CookieJar
+func (j *PersistentJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.jar.Cookies(u)
+}
+
+// Save writes the cookies (that haven't expired) to the file of the jar
+func (j *PersistentJar) Save() error {
+	j.mu.Lock()
+
+	now := time.Now()
+	keys := make([]string, 0, len(j.cookies))
+
+	for key, p := range j.cookies {
+		if !p.Expires.IsZero() && !p.Expires.After(now) {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	persisted := make([]persistedCookie, 0, len(keys))
+
+	for _, key := range keys {
+		persisted = append(persisted, j.cookies[key])
+	}
+
+	j.mu.Unlock()
+
+	contents, err := json.MarshalIndent(persisted, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	// The file is replaced atomically so a crash while saving doesn't lose the cookies
+	tmp, err := ioutil.TempFile(filepath.Dir(j.fileName), filepath.Base(j.fileName)+".tmp")
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), j.fileName)
+}
+
+// cookie converts the persisted cookie back into an http.Cookie
+func (p persistedCookie) cookie() *http.Cookie {
+	return &http.Cookie{
+		Name:     p.Name,
+		Value:    p.Value,
+		Domain:   p.Domain,
+		Path:     p.Path,
+		Expires:  p.Expires,
+		Secure:   p.Secure,
+		HttpOnly: p.HTTPOnly,
+		SameSite: p.SameSite,
+	}
+}
+
+// persistedCookieKey identifies a cookie the same way a cookie jar does (by domain, path and name)
+func persistedCookieKey(u *url.URL, c *http.Cookie) string {
+	domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+
+	if domain == "" {
+		domain = strings.ToLower(u.Hostname())
+	}
+
+	cookiePath := c.Path
+
+	if cookiePath == "" || cookiePath[0] != '/' {
+		cookiePath = defaultCookiePath(u.Path)
+	}
+
+	return domain + ";" + cookiePath + ";" + c.Name
+}
+
+// defaultCookiePath is the path of a cookie that doesn't specify one (RFC 6265 section 5.1.4)
+func defaultCookiePath(urlPath string) string {
+	if urlPath == "" || urlPath[0] != '/' || strings.Count(urlPath, "/") == 1 {
+		return "/"
+	}
+
+	return path.Dir(urlPath)
+}
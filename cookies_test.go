@@ -0,0 +1,116 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestSessionCookies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("token"); err == nil {
+			w.Write([]byte(c.Value))
+		}
+	}))
+	defer ts.Close()
+
+	session := NewSession(nil)
+
+	if err := session.SetCookies(ts.URL, []*http.Cookie{{Name: "token", Value: "abc"}}); err != nil {
+		t.Fatal("Unable to set cookies: ", err)
+	}
+
+	cookies, err := session.Cookies(ts.URL + "/path")
+
+	if err != nil || len(cookies) != 1 || cookies[0].Value != "abc" {
+		t.Fatal("Unexpected cookies: ", cookies, err)
+	}
+
+	resp, err := session.Get(ts.URL, nil)
+
+	if err != nil || resp.String() != "abc" {
+		t.Error("The cookie was not sent: ", err)
+	}
+
+	if _, err := (&Session{HTTPClient: &http.Client{}}).Cookies(ts.URL); err != ErrNoCookieJar {
+		t.Error("Expected ErrNoCookieJar, got: ", err)
+	}
+}
+
+func TestPersistentJar(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "1234", MaxAge: 3600})
+			http.SetCookie(w, &http.Cookie{Name: "temporary", Value: "x"})
+			http.SetCookie(w, &http.Cookie{Name: "scoped", Value: "y", Path: "/admin"})
+		case "/logout-temporary":
+			http.SetCookie(w, &http.Cookie{Name: "temporary", MaxAge: -1})
+		default:
+			c, _ := r.Cookie("session")
+
+			if c != nil {
+				w.Write([]byte(c.Value))
+			}
+		}
+	}))
+	defer ts.Close()
+
+	fileName := filepath.Join(t.TempDir(), "cookies.json")
+
+	jar, err := NewPersistentJar(fileName)
+
+	if err != nil {
+		t.Fatal("Unable to create jar: ", err)
+	}
+
+	session := NewSession(&RequestOptions{CookieJar: jar})
+
+	session.Get(ts.URL+"/login", nil)
+	session.Get(ts.URL+"/logout-temporary", nil)
+
+	if err := jar.Save(); err != nil {
+		t.Fatal("Unable to save jar: ", err)
+	}
+
+	reloaded, err := NewPersistentJar(fileName)
+
+	if err != nil {
+		t.Fatal("Unable to load jar: ", err)
+	}
+
+	session = NewSession(&RequestOptions{CookieJar: reloaded})
+
+	resp, err := session.Get(ts.URL+"/me", nil)
+
+	if err != nil || resp.String() != "1234" {
+		t.Error("The persisted cookie was not sent: ", err)
+	}
+
+	cookies, _ := session.Cookies(ts.URL + "/admin/users")
+
+	names := map[string]bool{}
+
+	for _, c := range cookies {
+		names[c.Name] = true
+	}
+
+	if len(cookies) != 2 || !names["session"] || !names["scoped"] {
+		t.Error("Unexpected cookies after reloading: ", cookies)
+	}
+
+	if cookies, _ := session.Cookies(ts.URL); len(cookies) != 1 {
+		t.Error("The path of the cookie was not persisted: ", cookies)
+	}
+}
+
+func TestDefaultCookiePath(t *testing.T) {
+	tests := map[string]string{"": "/", "/": "/", "/login": "/", "/a/b": "/a", "/a/b/": "/a/b"}
+
+	for urlPath, expected := range tests {
+		if cookiePath := defaultCookiePath(urlPath); cookiePath != expected {
+			t.Errorf("Expected the default path of %q to be %q, got %q", urlPath, expected, cookiePath)
+		}
+	}
+}
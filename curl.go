@@ -0,0 +1,155 @@
+package grequests
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ErrRequestBodyConsumed is the error returned by RequestAsCurl when the body of the request
+// has already been sent and can't be read again
+var ErrRequestBodyConsumed = errors.New("grequests: Request body has already been consumed")
+
+// ToCurl renders the request that would be sent with these options as a curl command
+// (including the headers, authentication, body and proxy). Building the request reads
+// the body so readers (e.g. Files or RequestBody) can't be used afterwards
+func (ro *RequestOptions) ToCurl(method, url string) (string, error) {
+	if ro == nil {
+		ro = &RequestOptions{}
+	}
+
+	req, err := prepareRequest(method, url, ro)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := runBeforeRequestHooks(req, ro.BeforeRequest); err != nil {
+		closeRequestBody(req)
+		return "", err
+	}
+
+	if err := authorizeRequest(ro, req); err != nil {
+		closeRequestBody(req)
+		return "", err
+	}
+
+	var body []byte
+
+	if req.Body != nil {
+		defer req.Body.Close()
+
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return "", err
+		}
+	}
+
+	var args []string
+
+	if ro.InsecureSkipVerify {
+		args = append(args, "--insecure")
+	}
+
+	if ro.ForceHTTP2 {
+		args = append(args, "--http2-prior-knowledge")
+	}
+
+	if ro.UnixSocket != "" {
+		args = append(args, "--unix-socket", shellQuote(ro.UnixSocket))
+	}
+
+	proxy, err := ro.proxySettings(req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if proxy != nil {
+		args = append(args, "--proxy", shellQuote(proxy.String()))
+	}
+
+	return curlCommand(req, body, args), nil
+}
+
+// RequestAsCurl renders the request that produced the response as a curl command. The proxy
+// and transport options of the request aren't included. ErrRequestBodyConsumed is returned if
+// the body of the request can't be read again
+func (r *Response) RequestAsCurl() (string, error) {
+	if r.request == nil {
+		return "", r.Error
+	}
+
+	var body []byte
+
+	if r.request.Body != nil && r.request.Body != http.NoBody {
+		if r.request.GetBody == nil {
+			return "", ErrRequestBodyConsumed
+		}
+
+		reader, err := r.request.GetBody()
+
+		if err != nil {
+			return "", err
+		}
+
+		defer reader.Close()
+
+		if body, err = ioutil.ReadAll(reader); err != nil {
+			return "", err
+		}
+	}
+
+	return curlCommand(r.request, body, nil), nil
+}
+
+// curlCommand renders the request (along with extra curl arguments) as a curl command
+func curlCommand(req *http.Request, body []byte, args []string) string {
+	command := []string{"curl"}
+
+	switch req.Method {
+	case "", http.MethodGet:
+	case http.MethodHead:
+		command = append(command, "--head")
+	default:
+		command = append(command, "-X", shellQuote(req.Method))
+	}
+
+	command = append(command, args...)
+
+	if req.Host != "" && req.Host != req.URL.Host {
+		command = append(command, "-H", shellQuote("Host: "+req.Host))
+	}
+
+	keys := make([]string, 0, len(req.Header))
+
+	for key := range req.Header {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range req.Header[key] {
+			command = append(command, "-H", shellQuote(key+": "+value))
+		}
+	}
+
+	if req.Header.Get("Accept-Encoding") != "" {
+		command = append(command, "--compressed")
+	}
+
+	if len(body) != 0 {
+		command = append(command, "--data-binary", shellQuote(string(body)))
+	}
+
+	command = append(command, shellQuote(req.URL.String()))
+
+	return strings.Join(command, " ")
+}
+
+// shellQuote quotes s so it is passed as a single argument by a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,85 @@
+package grequests
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestToCurl(t *testing.T) {
+	ro := &RequestOptions{
+		JSON:      map[string]string{"name": "O'Brien"},
+		Headers:   map[string]string{"X-Custom": "yes"},
+		Auth:      []string{"user", "pass"},
+		Params:    map[string]string{"q": "1"},
+		Proxy:     "http://proxy.example.com:3128",
+		UserAgent: "test-agent",
+	}
+
+	command, err := ro.ToCurl("POST", "https://example.com/users")
+
+	if err != nil {
+		t.Fatal("Unable to render curl command: ", err)
+	}
+
+	expected := `curl -X 'POST' --proxy 'http://proxy.example.com:3128' ` +
+		`-H 'Accept-Encoding: ` + acceptEncoding() + `' ` +
+		`-H 'Authorization: Basic dXNlcjpwYXNz' ` +
+		`-H 'Content-Type: application/json' ` +
+		`-H 'User-Agent: test-agent' ` +
+		`-H 'X-Custom: yes' --compressed ` +
+		"--data-binary '{\"name\":\"O'\\''Brien\"}\n' 'https://example.com/users?q=1'"
+
+	if command != expected {
+		t.Errorf("Unexpected curl command:\n%s\nexpected:\n%s", command, expected)
+	}
+
+	var nilOptions *RequestOptions
+
+	command, err = nilOptions.ToCurl("HEAD", "http://example.com")
+
+	if err != nil {
+		t.Fatal("Unable to render curl command: ", err)
+	}
+
+	if !strings.HasPrefix(command, "curl --head -H") || !strings.HasSuffix(command, "'http://example.com'") {
+		t.Error("Unexpected curl command: ", command)
+	}
+}
+
+func TestRequestAsCurl(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	resp, err := Put(ts.URL, &RequestOptions{Data: map[string]string{"a": "b"}, DisableCompression: true})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	command, err := resp.RequestAsCurl()
+
+	if err != nil {
+		t.Fatal("Unable to render curl command: ", err)
+	}
+
+	expected := `curl -X 'PUT' -H 'Content-Type: application/x-www-form-urlencoded' ` +
+		`-H 'User-Agent: ` + localUserAgent + `' --data-binary 'a=b' '` + ts.URL + `'`
+
+	if command != expected {
+		t.Errorf("Unexpected curl command:\n%s\nexpected:\n%s", command, expected)
+	}
+
+	resp, err = Post(ts.URL, &RequestOptions{RequestBody: ioutil.NopCloser(strings.NewReader("once"))})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if _, err := resp.RequestAsCurl(); !errors.Is(err, ErrRequestBodyConsumed) {
+		t.Error("Expected ErrRequestBodyConsumed, got: ", err)
+	}
+}
@@ -0,0 +1,96 @@
+package grequests
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+)
+
+// debugTransport writes a dump of every request (and its response) to RequestOptions.Debug
+type debugTransport struct {
+	transport http.RoundTripper
+	ro        *RequestOptions
+}
+
+func (d *debugTransport) maxBodySize() int64 {
+	if d.ro.DebugMaxBodySize == 0 {
+		return defaultLogMaxBodySize
+	}
+
+	return d.ro.DebugMaxBodySize
+}
+
+// RoundTrip dumps the request, sends it using the underlying transport and then dumps the response.
+// The start of each body is read (and then replayed) so it can be dumped
+func (d *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := d.transport
+
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	dump, err := httputil.DumpRequestOut(req, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && d.maxBodySize() > 0 {
+		// The request must not be modified so a copy is sent with the replayed body
+		req = req.Clone(req.Context())
+		req.Body, dump = d.dumpBody(req.Body, dump)
+	}
+
+	d.ro.Debug.Write(dump)
+
+	resp, err := transport.RoundTrip(req)
+
+	if err != nil {
+		fmt.Fprintf(d.ro.Debug, "grequests: %s %s error: %v\n\n", req.Method, req.URL.Redacted(), err)
+		return resp, err
+	}
+
+	dump, err = httputil.DumpResponse(resp, false)
+
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	if d.maxBodySize() > 0 {
+		resp.Body, dump = d.dumpBody(resp.Body, dump)
+	}
+
+	d.ro.Debug.Write(dump)
+
+	return resp, nil
+}
+
+// dumpBody appends the start of the body (up to the maximum size) to the dump. The returned body
+// replays the start of the body before reading the rest of it
+func (d *debugTransport) dumpBody(body io.ReadCloser, dump []byte) (io.ReadCloser, []byte) {
+	maxBodySize := d.maxBodySize()
+
+	start, readErr := ioutil.ReadAll(io.LimitReader(body, maxBodySize+1))
+
+	replayed := readCloser{
+		Reader: io.MultiReader(bytes.NewReader(start), &errorReader{Reader: body, err: readErr}),
+		Closer: body,
+	}
+
+	switch {
+	case len(start) == 0 && readErr == nil:
+		return replayed, dump
+	case readErr != nil:
+		dump = append(dump, fmt.Sprintf("[unable to read body: %v]", readErr)...)
+	case int64(len(start)) > maxBodySize:
+		dump = append(append(dump, start[:maxBodySize]...), " [truncated]"...)
+	default:
+		dump = append(dump, start...)
+	}
+
+	return replayed, append(dump, "\n\n"...)
+}
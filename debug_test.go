@@ -0,0 +1,79 @@
+package grequests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugDumpsRedirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/final", http.StatusFound)
+			return
+		}
+
+		w.Header().Set("X-Final", "yes")
+		w.Write([]byte("done"))
+	}))
+	defer ts.Close()
+
+	debug := &bytes.Buffer{}
+
+	resp, err := Post(ts.URL+"/redirect", &RequestOptions{Debug: debug, Data: map[string]string{"name": "levi"}})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if resp.String() != "done" {
+		t.Error("Dumping changed the body: ", resp.String())
+	}
+
+	dump := debug.String()
+
+	for _, expected := range []string{
+		"POST /redirect HTTP/1.1", "name=levi", "HTTP/1.1 302 Found",
+		"GET /final HTTP/1.1", "HTTP/1.1 200 OK", "X-Final: yes", "done",
+	} {
+		if !strings.Contains(dump, expected) {
+			t.Errorf("Dump doesn't contain %q: %s", expected, dump)
+		}
+	}
+}
+
+func TestDebugMaxBodySize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("0123456789"))
+	}))
+	defer ts.Close()
+
+	debug := &bytes.Buffer{}
+
+	resp, err := Post(ts.URL, &RequestOptions{Debug: debug, DebugMaxBodySize: 4, JSON: map[string]string{"a": "b"}})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if resp.String() != "0123456789" {
+		t.Error("Dumping changed the body: ", resp.String())
+	}
+
+	if dump := debug.String(); !strings.Contains(dump, "{\"a\" [truncated]") ||
+		!strings.Contains(dump, "0123 [truncated]") || strings.Contains(dump, "01234") {
+		t.Error("Bodies were not truncated: ", dump)
+	}
+
+	debug.Reset()
+
+	if _, err := Get(ts.URL, &RequestOptions{Debug: debug, DebugMaxBodySize: -1}); err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if dump := debug.String(); !strings.Contains(dump, "HTTP/1.1 200 OK") || strings.Contains(dump, "0123") {
+		t.Error("Body was dumped: ", dump)
+	}
+}
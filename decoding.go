@@ -0,0 +1,291 @@
+package grequests
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// ContentDecoder returns a reader that decodes a response body that has been
+// encoded with a specific Content-Encoding
+type ContentDecoder func(io.Reader) (io.Reader, error)
+
+var (
+	contentDecodersMu sync.RWMutex
+
+	// contentDecoders maps a Content-Encoding to a function that will decode it
+	contentDecoders = map[string]ContentDecoder{
+		"gzip":    newGzipDecoder,
+		"x-gzip":  newGzipDecoder,
+		"deflate": newDeflateDecoder,
+		"br":      newBrotliDecoder,
+	}
+
+	// advertisedEncodings are the encodings (in order of preference) that we send within
+	// the Accept-Encoding header of every request
+	advertisedEncodings = []string{"gzip", "deflate", "br"}
+)
+
+// RegisterContentDecoder adds a decoder for a Content-Encoding (e.g. snappy or lz4). Once it is
+// registered the encoding is advertised within the Accept-Encoding header of every request and
+// responses using the encoding are transparently decoded. Registering a decoder for an encoding
+// that already has one replaces it. Passing a nil decoder removes the encoding
+func RegisterContentDecoder(encoding string, decoder ContentDecoder) {
+	encoding = strings.ToLower(strings.TrimSpace(encoding))
+
+	contentDecodersMu.Lock()
+	defer contentDecodersMu.Unlock()
+
+	_, registered := contentDecoders[encoding]
+
+	if decoder == nil {
+		delete(contentDecoders, encoding)
+
+		for i, advertised := range advertisedEncodings {
+			if advertised == encoding {
+				advertisedEncodings = append(advertisedEncodings[:i:i], advertisedEncodings[i+1:]...)
+				break
+			}
+		}
+
+		return
+	}
+
+	contentDecoders[encoding] = decoder
+
+	if !registered {
+		advertisedEncodings = append(advertisedEncodings, encoding)
+	}
+}
+
+// acceptEncoding returns the Accept-Encoding header that we send with every request
+func acceptEncoding() string {
+	contentDecodersMu.RLock()
+	defer contentDecodersMu.RUnlock()
+
+	return strings.Join(advertisedEncodings, ", ")
+}
+
+// lookupContentDecoder returns the decoder for the Content-Encoding
+func lookupContentDecoder(encoding string) (ContentDecoder, bool) {
+	contentDecodersMu.RLock()
+	defer contentDecodersMu.RUnlock()
+
+	decoder, ok := contentDecoders[encoding]
+
+	return decoder, ok
+}
+
+func newGzipDecoder(r io.Reader) (io.Reader, error) {
+	return gzip.NewReader(r)
+}
+
+// newDeflateDecoder handles both zlib wrapped (which is what the RFC specifies) and raw deflate
+// streams (which is what a lot of servers actually send)
+func newDeflateDecoder(r io.Reader) (io.Reader, error) {
+	bufferedReader := bufio.NewReader(r)
+
+	header, err := bufferedReader.Peek(2)
+
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	// A zlib header is a CMF byte (deflate with a window <= 32K) followed by a FLG byte
+	// which makes the 16 bit header a multiple of 31
+	if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(bufferedReader)
+	}
+
+	return flate.NewReader(bufferedReader), nil
+}
+
+func newBrotliDecoder(r io.Reader) (io.Reader, error) {
+	return brotli.NewReader(r), nil
+}
+
+// parseContentEncodings returns the (lower case) encodings in the order they were applied
+func parseContentEncodings(header http.Header) []string {
+	var encodings []string
+
+	for _, value := range header["Content-Encoding"] {
+		for _, encoding := range strings.Split(value, ",") {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+
+			if encoding == "" || encoding == "identity" {
+				continue
+			}
+
+			encodings = append(encodings, encoding)
+		}
+	}
+
+	return encodings
+}
+
+// decodeResponseBody removes every content encoding that was applied to the body of the response
+// (in reverse order). If one of the encodings isn't supported the body is left as is. Responses that
+// can't have a body (HEAD requests, 204, 304 and an empty Content-Length) are left alone
+func decodeResponseBody(r *Response) error {
+	resp := r.RawResponse
+
+	if !responseHasBody(resp) {
+		return nil
+	}
+
+	encodings := parseContentEncodings(resp.Header)
+
+	if len(encodings) == 0 {
+		return nil
+	}
+
+	decoders := make([]ContentDecoder, len(encodings))
+
+	for i, encoding := range encodings {
+		decoder, ok := lookupContentDecoder(encoding)
+
+		if !ok {
+			return nil
+		}
+
+		decoders[i] = decoder
+	}
+
+	r.encodedCounter = &countingReader{Reader: resp.Body}
+
+	// The decoders are built on the first read as some of them (e.g. gzip) read the start of the body
+	r.decodedCounter = &countingReader{Reader: &lazyDecoder{body: r.encodedCounter, decoders: decoders}}
+
+	resp.Body = readCloser{Reader: r.decodedCounter, Closer: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return nil
+}
+
+// responseHasBody reports if the response may have a body
+func responseHasBody(resp *http.Response) bool {
+	if resp.Request != nil && resp.Request.Method == http.MethodHead {
+		return false
+	}
+
+	return resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotModified && resp.ContentLength != 0
+}
+
+// lazyDecoder applies the decoders (in reverse order) to the body the first time it is read
+type lazyDecoder struct {
+	body     io.Reader
+	decoders []ContentDecoder
+
+	decoded io.Reader
+	err     error
+}
+
+func (l *lazyDecoder) Read(p []byte) (int, error) {
+	if l.decoded == nil && l.err == nil {
+		l.decoded, l.err = l.decode()
+	}
+
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	return l.decoded.Read(p)
+}
+
+func (l *lazyDecoder) decode() (io.Reader, error) {
+	body := l.body
+
+	for i := len(l.decoders) - 1; i >= 0; i-- {
+		decoder, err := l.decoders[i](body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		body = decoder
+	}
+
+	return body, nil
+}
+
+// minRatioCheckSize is the amount of decoded bytes we allow before we start enforcing MaxCompressionRatio
+// (small bodies can have a very large compression ratio without being dangerous)
+const minRatioCheckSize = 1 << 20
+
+// limitResponseBody applies MaxResponseBodySize and MaxCompressionRatio to the (decoded) body of the response.
+// If the server declared a body larger than MaxResponseBodySize the response is closed without reading it
+func limitResponseBody(r *Response, ro *RequestOptions) error {
+	if ro.MaxResponseBodySize <= 0 && (ro.MaxCompressionRatio <= 0 || r.encodedCounter == nil) {
+		return nil
+	}
+
+	// The Content-Length of an encoded body isn't the size of the decoded body
+	if ro.MaxResponseBodySize > 0 && r.encodedCounter == nil && r.RawResponse.ContentLength > ro.MaxResponseBodySize {
+		r.RawResponse.Body.Close()
+
+		return fmt.Errorf("%w: Content-Length %d exceeds %d bytes",
+			ErrResponseBodyTooLarge, r.RawResponse.ContentLength, ro.MaxResponseBodySize)
+	}
+
+	r.RawResponse.Body = &bodyLimiter{
+		ReadCloser:     r.RawResponse.Body,
+		maxSize:        ro.MaxResponseBodySize,
+		maxRatio:       ro.MaxCompressionRatio,
+		encodedCounter: r.encodedCounter,
+	}
+
+	return nil
+}
+
+// bodyLimiter returns an error once the body exceeds the maximum size or compression ratio
+type bodyLimiter struct {
+	io.ReadCloser
+	maxSize        int64
+	maxRatio       float64
+	encodedCounter *countingReader
+	n              int64
+}
+
+func (b *bodyLimiter) Read(p []byte) (int, error) {
+	// Read one byte more than the limit so we can tell if the body is too large
+	if b.maxSize > 0 && int64(len(p)) > b.maxSize-b.n+1 {
+		p = p[:b.maxSize-b.n+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+
+	if b.maxSize > 0 && b.n > b.maxSize {
+		return n - int(b.n-b.maxSize), ErrResponseBodyTooLarge
+	}
+
+	if b.maxRatio > 0 && b.encodedCounter != nil && b.n > minRatioCheckSize &&
+		float64(b.n) > b.maxRatio*float64(b.encodedCounter.n) {
+		return n, ErrCompressionRatioExceeded
+	}
+
+	return n, err
+}
+
+// countingReader counts the amount of bytes that have been read
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,329 @@
+package grequests
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+func gzipBytes(b []byte) []byte {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	w.Write(b)
+	w.Close()
+	return buf.Bytes()
+}
+
+func brotliBytes(b []byte) []byte {
+	buf := &bytes.Buffer{}
+	w := brotli.NewWriter(buf)
+	w.Write(b)
+	w.Close()
+	return buf.Bytes()
+}
+
+func deflateBytes(b []byte) []byte {
+	buf := &bytes.Buffer{}
+	w, _ := flate.NewWriter(buf, flate.DefaultCompression)
+	w.Write(b)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestStackedContentEncoding(t *testing.T) {
+	body := bytes.Repeat([]byte("grequests "), 100)
+	encodedBody := brotliBytes(gzipBytes(body))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip, br")
+		w.Write(encodedBody)
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if !bytes.Equal(resp.Bytes(), body) {
+		t.Fatal("Body was not decoded: ", resp.String())
+	}
+
+	if resp.Header.Get("Content-Encoding") != "" {
+		t.Error("Content-Encoding header was not removed")
+	}
+
+	if resp.EncodedSize() != int64(len(encodedBody)) || resp.DecodedSize() != int64(len(body)) {
+		t.Error("Invalid sizes: ", resp.EncodedSize(), resp.DecodedSize())
+	}
+}
+
+func TestRawDeflateContentEncoding(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "deflate")
+		w.Write(deflateBytes([]byte("raw deflate")))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "raw deflate" {
+		t.Error("Body was not decoded: ", resp.String())
+	}
+}
+
+func TestUnknownContentEncodingIsLeftAlone(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip, unknown")
+		w.Write([]byte("opaque"))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "opaque" || resp.Header.Get("Content-Encoding") != "gzip, unknown" {
+		t.Error("Body with an unknown encoding was modified: ", resp.String())
+	}
+
+	if resp.EncodedSize() != -1 {
+		t.Error("Encoded size should be unknown: ", resp.EncodedSize())
+	}
+}
+
+func TestMaxResponseBodySizeAppliesToDecodedBody(t *testing.T) {
+	bomb := gzipBytes(make([]byte, 10<<20))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip, identity")
+		w.Write(bomb)
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, &RequestOptions{MaxResponseBodySize: 1 << 20})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	resp.Bytes()
+
+	if resp.Error != ErrResponseBodyTooLarge {
+		t.Error("Decoded body size was not limited: ", resp.Error)
+	}
+}
+
+func TestMaxCompressionRatio(t *testing.T) {
+	bomb := gzipBytes(make([]byte, 10<<20))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip, identity")
+		w.Write(bomb)
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, &RequestOptions{MaxCompressionRatio: 100})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	resp.Bytes()
+
+	if resp.Error != ErrCompressionRatioExceeded {
+		t.Error("Compression ratio was not limited: ", resp.Error)
+	}
+}
+
+func TestMaxResponseBodySizeExactLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("0123456789"))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, &RequestOptions{MaxResponseBodySize: 10})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "0123456789" || resp.Error != nil {
+		t.Error("Body within the limit was rejected: ", resp.Error)
+	}
+}
+
+func TestMaxCompressionRatioGzipResponse(t *testing.T) {
+	bomb := gzipBytes(make([]byte, 10<<20))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip, deflate, br" {
+			t.Error("Accept-Encoding was not sent: ", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(bomb)
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, &RequestOptions{MaxCompressionRatio: 100})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	resp.Bytes()
+
+	if resp.Error != ErrCompressionRatioExceeded {
+		t.Error("Compression ratio was not limited: ", resp.Error)
+	}
+}
+
+func TestRegisterContentDecoder(t *testing.T) {
+	// A toy encoding that reverses the body
+	RegisterContentDecoder("X-Reverse", func(r io.Reader) (io.Reader, error) {
+		b, err := ioutil.ReadAll(r)
+
+		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+
+		return bytes.NewReader(b), err
+	})
+	defer RegisterContentDecoder("x-reverse", nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip, deflate, br, x-reverse" {
+			t.Error("Custom encoding was not advertised: ", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "x-reverse, gzip")
+		w.Write(gzipBytes([]byte("stseuqerg")))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "grequests" {
+		t.Error("Custom encoding was not decoded: ", resp.String())
+	}
+
+	RegisterContentDecoder("x-reverse", nil)
+
+	if acceptEncoding() != "gzip, deflate, br" {
+		t.Error("Custom encoding was not removed: ", acceptEncoding())
+	}
+}
+
+func TestMaxResponseBodySizeDeclaredContentLength(t *testing.T) {
+	var attempts int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Length", "11")
+		w.Write([]byte("01234567890"))
+	}))
+	defer ts.Close()
+
+	_, err := Get(ts.URL, &RequestOptions{MaxResponseBodySize: 10, RetryPolicy: &BackoffRetryPolicy{}})
+
+	if !errors.Is(err, ErrResponseBodyTooLarge) {
+		t.Fatal("Expected ErrResponseBodyTooLarge, got: ", err)
+	}
+
+	if attempts != 1 {
+		t.Error("A response that is too large was retried: ", attempts)
+	}
+}
+
+func TestResponseHeaderTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+
+	_, err := Get(ts.URL, &RequestOptions{ResponseHeaderTimeout: 20 * time.Millisecond})
+
+	if !errors.Is(err, ErrTimeout) {
+		t.Error("Expected ErrTimeout, got: ", err)
+	}
+}
+
+func TestGzipResponsesWithoutBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+
+		switch r.URL.Path {
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/not-modified":
+			w.WriteHeader(http.StatusNotModified)
+		case "/empty":
+			w.Header().Set("Content-Length", "0")
+		default:
+			w.Write(gzipBytes([]byte("Hello World")))
+		}
+	}))
+	defer ts.Close()
+
+	for _, path := range []string{"/no-content", "/not-modified", "/empty"} {
+		resp, err := Get(ts.URL+path, nil)
+
+		if err != nil {
+			t.Fatalf("%s failed: %v", path, err)
+		}
+
+		if body := resp.String(); resp.Error != nil || body != "" {
+			t.Errorf("%s: unexpected body %q: %v", path, body, resp.Error)
+		}
+	}
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil || resp.String() != "Hello World" {
+		t.Error("GET of the gzip encoded resource failed: ", err, resp.Error)
+	}
+}
+
+func TestHeadGzipEncodingServer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") == "" {
+			t.Error("Accept-Encoding was not sent")
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(gzipBytes([]byte("Hello World")))
+	}))
+	defer ts.Close()
+
+	resp, err := Head(ts.URL, nil)
+
+	if err != nil || !resp.Ok {
+		t.Fatal("HEAD of a gzip encoded resource failed: ", err)
+	}
+
+	if body := resp.String(); resp.Error != nil || body != "" {
+		t.Errorf("Unexpected HEAD body %q: %v", body, resp.Error)
+	}
+}
@@ -0,0 +1,108 @@
+package grequests
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	// defaultOptions are the options set by SetDefaultOptions
+	defaultOptions   *RequestOptions
+	defaultOptionsMu sync.RWMutex
+)
+
+// SetDefaultOptions sets the options that every request is made with (including the requests of
+// a Session). The options of a request are merged on top of the defaults: the entries of the maps
+// (e.g. Headers and Params) are merged, the BeforeRequest and AfterResponse hooks of the defaults
+// run ahead of those of the request and every other option that is set (isn't the zero value)
+// replaces the default. Pass nil to remove the defaults
+func SetDefaultOptions(ro *RequestOptions) {
+	defaultOptionsMu.Lock()
+	defer defaultOptionsMu.Unlock()
+
+	defaultOptions = copyRequestOptions(ro)
+}
+
+// SetDefaultOptions sets the options that every request of the session is made with. They are
+// merged on top of the package defaults (and the options of a request on top of them) just like
+// SetDefaultOptions. The options that configure the client (e.g. proxies, timeouts and TLS) are
+// ignored because the session has its own client, pass those to NewSession instead
+func (s *Session) SetDefaultOptions(ro *RequestOptions) {
+	s.defaultsMu.Lock()
+	defer s.defaultsMu.Unlock()
+
+	s.defaults = copyRequestOptions(ro)
+}
+
+// withDefaultOptions returns the request options merged on top of the package defaults
+func withDefaultOptions(ro *RequestOptions) *RequestOptions {
+	defaultOptionsMu.RLock()
+	defer defaultOptionsMu.RUnlock()
+
+	return mergeRequestOptions(defaultOptions, ro)
+}
+
+// withDefaultOptions returns the request options merged on top of the package and session defaults
+func (s *Session) withDefaultOptions(ro *RequestOptions) *RequestOptions {
+	s.defaultsMu.RLock()
+	defer s.defaultsMu.RUnlock()
+
+	return withDefaultOptions(mergeRequestOptions(s.defaults, ro))
+}
+
+// copyRequestOptions returns a copy of the options (so the user can't modify them later on)
+func copyRequestOptions(ro *RequestOptions) *RequestOptions {
+	if ro == nil {
+		return nil
+	}
+
+	return mergeRequestOptions(&RequestOptions{}, ro)
+}
+
+// mergeRequestOptions returns a copy of the defaults with the options merged on top. Neither
+// of the options are modified
+func mergeRequestOptions(defaults, ro *RequestOptions) *RequestOptions {
+	if defaults == nil {
+		return ro
+	}
+
+	merged := *defaults
+
+	if ro == nil {
+		ro = &RequestOptions{}
+	}
+
+	mergedValue := reflect.ValueOf(&merged).Elem()
+	roValue := reflect.ValueOf(ro).Elem()
+
+	for i := 0; i < mergedValue.NumField(); i++ {
+		field := mergedValue.Field(i)
+		value := roValue.Field(i)
+
+		switch {
+		case field.Kind() == reflect.Map && (!field.IsNil() || !value.IsNil()):
+			// A new map is created so the maps of the defaults (and of the user) are never shared
+			entries := reflect.MakeMapWithSize(field.Type(), field.Len()+value.Len())
+
+			for _, source := range []reflect.Value{field, value} {
+				for iter := source.MapRange(); iter.Next(); {
+					entries.SetMapIndex(iter.Key(), iter.Value())
+				}
+			}
+
+			field.Set(entries)
+		case field.Kind() == reflect.Slice && isHookField(mergedValue.Type().Field(i).Name) && value.Len() != 0:
+			field.Set(reflect.AppendSlice(reflect.MakeSlice(field.Type(), 0, field.Len()+value.Len()), field))
+			field.Set(reflect.AppendSlice(field, value))
+		case !value.IsZero():
+			field.Set(value)
+		}
+	}
+
+	return &merged
+}
+
+// isHookField reports if the field of RequestOptions contains hooks (which are run in order)
+func isHookField(name string) bool {
+	return name == "BeforeRequest" || name == "AfterResponse"
+}
@@ -0,0 +1,94 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeRequestOptions(t *testing.T) {
+	var calls []string
+
+	defaults := &RequestOptions{
+		Headers:       map[string]string{"X-Default": "1", "X-Override": "default"},
+		UserAgent:     "default",
+		DialTimeout:   time.Second,
+		BeforeRequest: []func(*http.Request) error{func(*http.Request) error { calls = append(calls, "default"); return nil }},
+	}
+
+	ro := &RequestOptions{
+		Headers:       map[string]string{"X-Override": "request"},
+		DialTimeout:   2 * time.Second,
+		BeforeRequest: []func(*http.Request) error{func(*http.Request) error { calls = append(calls, "request"); return nil }},
+	}
+
+	merged := mergeRequestOptions(defaults, ro)
+
+	if !reflect.DeepEqual(merged.Headers, map[string]string{"X-Default": "1", "X-Override": "request"}) {
+		t.Error("Unexpected headers: ", merged.Headers)
+	}
+
+	if merged.UserAgent != "default" || merged.DialTimeout != 2*time.Second {
+		t.Error("Unexpected options: ", merged.UserAgent, merged.DialTimeout)
+	}
+
+	for _, hook := range merged.BeforeRequest {
+		hook(nil)
+	}
+
+	if !reflect.DeepEqual(calls, []string{"default", "request"}) {
+		t.Error("Unexpected hooks: ", calls)
+	}
+
+	// Neither of the options are modified
+	merged.Headers["X-Merged"] = "1"
+
+	if len(defaults.Headers) != 2 || len(ro.Headers) != 1 || len(defaults.BeforeRequest) != 1 {
+		t.Error("The options were modified: ", defaults.Headers, ro.Headers)
+	}
+
+	if mergeRequestOptions(nil, ro) != ro {
+		t.Error("Expected the options to be returned as is without defaults")
+	}
+}
+
+func TestDefaultOptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.UserAgent() + "|" + r.Header.Get("X-Team") + "|" + r.Header.Get("X-Session") + "|" + r.URL.RawQuery))
+	}))
+	defer ts.Close()
+
+	SetDefaultOptions(&RequestOptions{UserAgent: "default-agent", Headers: map[string]string{"X-Team": "payments"}})
+	defer SetDefaultOptions(nil)
+
+	resp, err := Get(ts.URL, &RequestOptions{Params: map[string]string{"a": "1"}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.String() != "default-agent|payments||a=1" {
+		t.Error("Unexpected request: ", resp.String())
+	}
+
+	session := NewSession(nil)
+	session.SetDefaultOptions(&RequestOptions{Headers: map[string]string{"X-Session": "1", "X-Team": "session"}})
+
+	resp, err = session.Get(ts.URL, &RequestOptions{UserAgent: "request-agent"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.String() != "request-agent|session|1|" {
+		t.Error("Unexpected request: ", resp.String())
+	}
+
+	SetDefaultOptions(nil)
+
+	if resp, _ := Get(ts.URL, nil); resp.String() != localUserAgent+"|||" {
+		t.Error("Expected the defaults to be removed: ", resp.String())
+	}
+}
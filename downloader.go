@@ -0,0 +1,266 @@
+package grequests
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// Default value for DownloadManager Concurrency
+	defaultDownloadConcurrency = 4
+
+	// Default value for DownloadManager Retries
+	defaultDownloadRetries = 3
+
+	// Default value for DownloadManager RetryDelay
+	defaultDownloadRetryDelay = time.Second
+)
+
+// DownloadItem is a single file that the DownloadManager will download
+type DownloadItem struct {
+	// URL is the location of the file
+	URL string
+
+	// Path is where the file will be saved on the disk
+	Path string
+
+	// RequestOptions are the options used when requesting the URL (may be nil)
+	RequestOptions *RequestOptions
+}
+
+// DownloadResult is the outcome of a DownloadItem
+type DownloadResult struct {
+	// Item is the item that was downloaded
+	Item DownloadItem
+
+	// BytesWritten is the amount of bytes written to the disk (not including any bytes
+	// that were already on the disk before resuming)
+	BytesWritten int64
+
+	// Resumed is true if the download continued from a partially downloaded file
+	Resumed bool
+
+	// Attempts is the amount of times we tried downloading the item
+	Attempts int
+
+	// Error is the error that caused the download to fail (if any)
+	Error error
+}
+
+// DownloadProgress is the aggregated progress of every item enqueued into the DownloadManager
+type DownloadProgress struct {
+	// Enqueued is the amount of items that have been enqueued
+	Enqueued int
+
+	// Completed is the amount of items that have finished (successfully or not)
+	Completed int
+
+	// Failed is the amount of items that have failed
+	Failed int
+
+	// BytesDownloaded is the amount of bytes that have been downloaded (including any
+	// bytes that were already on the disk before resuming)
+	BytesDownloaded int64
+
+	// BytesTotal is the total amount of bytes of the items that have started downloading.
+	// Items that didn't provide a Content-Length are not included
+	BytesTotal int64
+}
+
+// DownloadManager downloads many files concurrently. Items are enqueued with Enqueue and
+// Wait blocks until every enqueued item has finished
+type DownloadManager struct {
+	// Concurrency is the maximum amount of items that will be downloaded at the same time. The default is 4
+	Concurrency int
+
+	// Retries is the amount of times a failed download is retried. The default is 3. A negative number
+	// disables retries
+	Retries int
+
+	// RetryDelay is the amount of time to wait before retrying a download. The delay is multiplied by the
+	// attempt number. The default is 1 second
+	RetryDelay time.Duration
+
+	// Resume will continue downloading partially downloaded files (using a Range request) instead of
+	// starting from the beginning. This also applies to retries. The validator of each file is kept next to
+	// it while it downloads so a file that changed on the server is downloaded again (see DownloadResumable)
+	Resume bool
+
+	// Session (if set) is used to download every item
+	Session *Session
+
+	// Progress (if set) is called with the aggregated progress every time bytes are written to the disk
+	Progress func(DownloadProgress)
+
+	// OnComplete (if set) is called once an item has finished (successfully or not)
+	OnComplete func(DownloadResult)
+
+	once      sync.Once
+	semaphore chan struct{}
+	wg        sync.WaitGroup
+
+	mu       sync.Mutex
+	progress DownloadProgress
+	results  []DownloadResult
+}
+
+// Enqueue adds an item to the download queue. The download starts as soon as there is capacity
+func (m *DownloadManager) Enqueue(item DownloadItem) {
+	m.once.Do(func() {
+		concurrency := m.Concurrency
+
+		if concurrency <= 0 {
+			concurrency = defaultDownloadConcurrency
+		}
+
+		m.semaphore = make(chan struct{}, concurrency)
+	})
+
+	m.mu.Lock()
+	m.progress.Enqueued++
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+
+	go func() {
+		defer m.wg.Done()
+
+		m.semaphore <- struct{}{}
+		result := m.download(item)
+		<-m.semaphore
+
+		m.mu.Lock()
+		m.results = append(m.results, result)
+		m.progress.Completed++
+
+		if result.Error != nil {
+			m.progress.Failed++
+		}
+
+		progress := m.progress
+		m.mu.Unlock()
+
+		if m.Progress != nil {
+			m.Progress(progress)
+		}
+
+		if m.OnComplete != nil {
+			m.OnComplete(result)
+		}
+	}()
+}
+
+// Wait blocks until every enqueued item has finished and returns the results (in the order the
+// items finished)
+func (m *DownloadManager) Wait() []DownloadResult {
+	m.wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]DownloadResult(nil), m.results...)
+}
+
+func (m *DownloadManager) retries() int {
+	switch {
+	case m.Retries < 0:
+		return 0
+	case m.Retries == 0:
+		return defaultDownloadRetries
+	}
+
+	return m.Retries
+}
+
+func (m *DownloadManager) retryDelay() time.Duration {
+	if m.RetryDelay == 0 {
+		return defaultDownloadRetryDelay
+	}
+
+	return m.RetryDelay
+}
+
+func (m *DownloadManager) download(item DownloadItem) DownloadResult {
+	result := DownloadResult{Item: item}
+
+	for attempt := 0; attempt <= m.retries(); attempt++ {
+		if attempt > 0 {
+			time.Sleep(m.retryDelay() * time.Duration(attempt))
+		}
+
+		result.Attempts++
+
+		if result.Error = m.downloadOnce(item, &result); result.Error == nil {
+			break
+		}
+	}
+
+	return result
+}
+
+// downloadOnce makes a single attempt at downloading the item
+func (m *DownloadManager) downloadOnce(item DownloadItem, result *DownloadResult) error {
+	get := func(ro *RequestOptions) (*Response, error) {
+		if m.Session != nil {
+			return m.Session.Get(item.URL, ro)
+		}
+
+		return Get(item.URL, ro)
+	}
+
+	download := rangeDownload{
+		url:    item.URL,
+		path:   item.Path,
+		resume: m.Resume,
+		started: func(offset, total int64) {
+			m.addProgress(offset, total)
+		},
+		progress: func(n int64) {
+			m.addProgress(n, 0)
+		},
+	}
+
+	downloaded, err := download.run(get, item.RequestOptions)
+
+	result.BytesWritten += downloaded.written
+	result.Resumed = result.Resumed || downloaded.resumed
+
+	if err != nil && downloaded.started {
+		// The next attempt will account for these bytes again
+		m.addProgress(-(downloaded.offset + downloaded.written), -downloaded.total)
+	}
+
+	return err
+}
+
+// addProgress adds the downloaded and total bytes to the aggregated progress
+func (m *DownloadManager) addProgress(downloaded, total int64) {
+	m.mu.Lock()
+
+	m.progress.BytesDownloaded += downloaded
+	m.progress.BytesTotal += total
+
+	progress := m.progress
+	m.mu.Unlock()
+
+	if m.Progress != nil {
+		m.Progress(progress)
+	}
+}
+
+// progressReader calls progress with the amount of bytes returned by each read
+type progressReader struct {
+	io.Reader
+	progress func(n int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.Reader.Read(b)
+
+	if n > 0 {
+		p.progress(int64(n))
+	}
+
+	return n, err
+}
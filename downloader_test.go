@@ -0,0 +1,91 @@
+package grequests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownloadManager(t *testing.T) {
+	const contents = "0123456789"
+
+	var failures int32 = 1
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/flaky" && atomic.AddInt32(&failures, -1) >= 0 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "file", time.Time{}, strings.NewReader(contents))
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "grequests")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	// A partially downloaded file
+	if err := ioutil.WriteFile(filepath.Join(dir, "partial"), []byte("01234"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "partial"+validatorSuffix), []byte(`"v1"`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var completed int32
+
+	m := &DownloadManager{
+		Concurrency: 2,
+		RetryDelay:  1,
+		Resume:      true,
+		OnComplete:  func(DownloadResult) { atomic.AddInt32(&completed, 1) },
+	}
+
+	m.Enqueue(DownloadItem{URL: ts.URL + "/full", Path: filepath.Join(dir, "full")})
+	m.Enqueue(DownloadItem{URL: ts.URL + "/partial", Path: filepath.Join(dir, "partial")})
+	m.Enqueue(DownloadItem{URL: ts.URL + "/flaky", Path: filepath.Join(dir, "flaky")})
+
+	results := m.Wait()
+
+	if len(results) != 3 || atomic.LoadInt32(&completed) != 3 {
+		t.Fatal("Not every item completed: ", results)
+	}
+
+	for _, result := range results {
+		if result.Error != nil {
+			t.Error("Download failed: ", result.Item.URL, result.Error)
+		}
+
+		if b, _ := ioutil.ReadFile(result.Item.Path); string(b) != contents {
+			t.Error("Invalid file contents: ", result.Item.Path, string(b))
+		}
+
+		switch filepath.Base(result.Item.Path) {
+		case "partial":
+			if !result.Resumed || result.BytesWritten != 5 {
+				t.Error("Download was not resumed: ", result)
+			}
+		case "flaky":
+			if result.Attempts != 2 {
+				t.Error("Download was not retried: ", result)
+			}
+		}
+	}
+
+	if m.progress.BytesDownloaded != 30 || m.progress.BytesTotal != 30 || m.progress.Completed != 3 {
+		t.Error("Invalid progress: ", m.progress)
+	}
+}
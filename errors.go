@@ -0,0 +1,90 @@
+package grequests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Non2xxError is the error returned by RaiseForStatus (and by requests sent with the
+// RaiseForStatus option) when the server didn't respond with a 2xx status code
+type Non2xxError struct {
+	// StatusCode is the status code of the response
+	StatusCode int
+
+	// Status is the status line of the response e.g. "404 Not Found"
+	Status string
+
+	// URL is the URL of the (last) request
+	URL string
+
+	// Body is the body of the response
+	Body []byte
+}
+
+func (e *Non2xxError) Error() string {
+	return fmt.Sprintf("grequests: %s returned %s", e.URL, e.Status)
+}
+
+// RaiseForStatus returns a *Non2xxError if the server didn't respond with a 2xx status code
+// (or the error of the request if it failed). The body is read into the error but remains
+// available through the methods of the response
+func (r *Response) RaiseForStatus() error {
+	if r.Error != nil {
+		return r.Error
+	}
+
+	if r.Ok {
+		return nil
+	}
+
+	body := r.Bytes()
+
+	if r.Error != nil {
+		return r.Error
+	}
+
+	err := &Non2xxError{StatusCode: r.StatusCode, Status: r.RawResponse.Status, Body: body}
+
+	if r.RawResponse.Request != nil {
+		err.URL = r.RawResponse.Request.URL.String()
+	}
+
+	return err
+}
+
+// timeoutError wraps an error caused by a timeout so it matches ErrTimeout
+type timeoutError struct {
+	err error
+}
+
+func (e *timeoutError) Error() string {
+	return e.err.Error()
+}
+
+// Timeout allows timeoutError to be used as a net.Error
+func (e *timeoutError) Timeout() bool {
+	return true
+}
+
+// Is allows errors.Is(err, ErrTimeout)
+func (e *timeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// Unwrap allows the original error to be inspected
+func (e *timeoutError) Unwrap() error {
+	return e.err
+}
+
+// wrapTimeout wraps err in a timeoutError if it was caused by a timeout
+func wrapTimeout(err error) error {
+	var netErr net.Error
+
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &timeoutError{err: err}
+	}
+
+	return err
+}
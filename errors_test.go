@@ -0,0 +1,108 @@
+package grequests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRaiseForStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ok" {
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"not found"}`))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL+"/missing", &RequestOptions{RaiseForStatus: true})
+
+	var statusErr *Non2xxError
+
+	if !errors.As(err, &statusErr) {
+		t.Fatal("Expected a *Non2xxError, got: ", err)
+	}
+
+	if statusErr.StatusCode != http.StatusNotFound || statusErr.Status != "404 Not Found" ||
+		statusErr.URL != ts.URL+"/missing" || string(statusErr.Body) != `{"error":"not found"}` {
+		t.Errorf("Unexpected error: %+v", statusErr)
+	}
+
+	if resp.String() != `{"error":"not found"}` {
+		t.Error("Body is no longer readable: ", resp.String())
+	}
+
+	resp, err = Get(ts.URL+"/ok", &RequestOptions{RaiseForStatus: true})
+
+	if err != nil || resp.RaiseForStatus() != nil {
+		t.Error("A 2xx response returned an error: ", err)
+	}
+
+	resp, err = Get(ts.URL+"/missing", nil)
+
+	if err != nil {
+		t.Fatal("Request returned an error without RaiseForStatus: ", err)
+	}
+
+	if !errors.As(resp.RaiseForStatus(), &statusErr) {
+		t.Error("RaiseForStatus didn't return a *Non2xxError")
+	}
+}
+
+func TestErrTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+
+	_, err := Get(ts.URL, &RequestOptions{AttemptTimeout: 10 * time.Millisecond})
+
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected a timeout error, got: ", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Get(ts.URL, &RequestOptions{Context: ctx}); errors.Is(err, ErrTimeout) {
+		t.Error("A cancelled request was reported as a timeout: ", err)
+	}
+}
+
+func TestErrTooManyRedirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/", http.StatusFound)
+	}))
+	defer ts.Close()
+
+	if _, err := Get(ts.URL, &RequestOptions{RedirectLimit: 2}); !errors.Is(err, ErrTooManyRedirects) {
+		t.Error("Expected ErrTooManyRedirects, got: ", err)
+	}
+}
+
+func TestErrFileUpload(t *testing.T) {
+	_, err := FileUploadFromDisk("file-that-does-not-exist")
+
+	if !errors.Is(err, ErrFileUpload) || !errors.Is(err, os.ErrNotExist) {
+		t.Error("Expected a file upload error, got: ", err)
+	}
+
+	if _, err := FileUploadFromGlob("file-that-does-not-exist*"); !errors.Is(err, ErrFileUpload) {
+		t.Error("Expected a file upload error, got: ", err)
+	}
+
+	_, err = Post("http://localhost", &RequestOptions{Files: []FileUpload{{FileName: "nil.txt"}}})
+
+	if !errors.Is(err, ErrFileUpload) {
+		t.Error("Expected a file upload error, got: ", err)
+	}
+}
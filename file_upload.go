@@ -1,8 +1,11 @@
 package grequests
 
 import (
-	"errors"
+	"bytes"
+	"fmt"
 	"io"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
 )
@@ -15,22 +18,39 @@ type FileUpload struct {
 
 	// FileContents is happy as long as you pass it a io.ReadCloser (which most file use anyways)
 	FileContents io.ReadCloser
+
+	// FieldName is the name of the form field the file is sent as. If it is empty the field is named
+	// "file" (or "file1", "file2", ... when several files are uploaded). Several files may share a field name
+	FieldName string
+
+	// ContentType is the MIME type of the file. If it is empty we will try to guess it from the file name
+	// and then (if the extension isn't known) from the first 512 bytes of the file
+	ContentType string
+
+	// TransferEncoding is the Content-Transfer-Encoding of the file when it is sent within a multipart
+	// body. The file will be encoded on the fly. See TransferEncodingBase64 and TransferEncodingQuotedPrintable
+	TransferEncoding string
+
+	// Headers are extra headers of the part (e.g. Content-ID). The Content-Disposition and Content-Type
+	// of the part are set from FieldName, FileName and ContentType so they can't be overridden here
+	Headers map[string]string
 }
 
 // FileUploadFromDisk allows you to create a FileUpload struct slice by just specifying a location on the disk
 func FileUploadFromDisk(fileName string) ([]FileUpload, error) {
-	fd, err := os.Open(fileName)
+	fd, err := openFileUpload(fileName)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return []FileUpload{{FileContents: fd, FileName: fileName}}, nil
+	return []FileUpload{fd}, nil
 
 }
 
 // FileUploadFromGlob allows you to create a FileUpload struct slice by just specifying a glob location on the disk
-// this function will gloss over all errors in the files and only upload the files that don't return errors from the glob
+// this function will skip over any directories that match the glob, but will return an error if one of the
+// files cannot be opened (closing any files that have already been opened)
 func FileUploadFromGlob(fileSystemGlob string) ([]FileUpload, error) {
 	files, err := filepath.Glob(fileSystemGlob)
 
@@ -39,7 +59,7 @@ func FileUploadFromGlob(fileSystemGlob string) ([]FileUpload, error) {
 	}
 
 	if len(files) == 0 {
-		return nil, errors.New("grequests: No files have been returned in the glob")
+		return nil, fmt.Errorf("%w: No files have been returned in the glob", ErrFileUpload)
 	}
 
 	filesToUpload := make([]FileUpload, 0, len(files))
@@ -49,13 +69,94 @@ func FileUploadFromGlob(fileSystemGlob string) ([]FileUpload, error) {
 			continue
 		}
 
-		// ignoring error because I can stat the file
-		fd, _ := os.Open(f)
+		fd, err := openFileUpload(f)
 
-		filesToUpload = append(filesToUpload, FileUpload{FileContents: fd, FileName: filepath.Base(fd.Name())})
+		if err != nil {
+			closeFileUploads(filesToUpload)
+			return nil, err
+		}
+
+		filesToUpload = append(filesToUpload, fd)
 
 	}
 
 	return filesToUpload, nil
 
 }
+
+// openFileUpload opens the file on the disk and infers the upload name and MIME type from the file path
+func openFileUpload(fileName string) (FileUpload, error) {
+	fd, err := os.Open(fileName)
+
+	if err != nil {
+		return FileUpload{}, &fileUploadError{err: err}
+	}
+
+	return FileUpload{
+		FileContents: fd,
+		FileName:     filepath.Base(fileName),
+		ContentType:  mime.TypeByExtension(filepath.Ext(fileName)),
+	}, nil
+}
+
+// detectContentType returns the MIME type of the file upload along with a reader which will return the
+// entire file contents. If the MIME type cannot be guessed using the file extension we will sniff the
+// first 512 bytes of the file (using http.DetectContentType) – these bytes are replayed by the returned reader
+func (f FileUpload) detectContentType() (string, io.ReadCloser, error) {
+	if f.ContentType != "" {
+		return f.ContentType, f.FileContents, nil
+	}
+
+	if contentType := mime.TypeByExtension(filepath.Ext(f.FileName)); contentType != "" {
+		return contentType, f.FileContents, nil
+	}
+
+	sniffBuffer := make([]byte, 512)
+
+	n, err := io.ReadFull(f.FileContents, sniffBuffer)
+
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+
+	sniffBuffer = sniffBuffer[:n]
+
+	return http.DetectContentType(sniffBuffer), readCloser{
+		Reader: io.MultiReader(bytes.NewReader(sniffBuffer), f.FileContents),
+		Closer: f.FileContents,
+	}, nil
+}
+
+// readCloser glues together a reader and the closer of the underlying resource
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// fileUploadError wraps an error opening a file so it matches ErrFileUpload
+type fileUploadError struct {
+	err error
+}
+
+func (e *fileUploadError) Error() string {
+	return ErrFileUpload.Error() + ": " + e.err.Error()
+}
+
+// Is allows errors.Is(err, ErrFileUpload)
+func (e *fileUploadError) Is(target error) bool {
+	return target == ErrFileUpload
+}
+
+// Unwrap allows the original error (e.g. *os.PathError) to be inspected
+func (e *fileUploadError) Unwrap() error {
+	return e.err
+}
+
+// closeFileUploads closes every file within the slice – it is used to clean up after a failure
+func closeFileUploads(files []FileUpload) {
+	for _, f := range files {
+		if f.FileContents != nil {
+			f.FileContents.Close()
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package grequests
 
-import "testing"
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestErrorOpenFile(t *testing.T) {
 	fd, err := FileUploadFromDisk("I am Not A File")
@@ -51,3 +58,238 @@ func TestGlobWithDir(t *testing.T) {
 	}
 
 }
+
+func TestFileUploadFromDiskInfersName(t *testing.T) {
+	fd, err := FileUploadFromDisk("test_files/mypassword")
+
+	if err != nil {
+		t.Fatal("Unable to open file: ", err)
+	}
+
+	defer closeFileUploads(fd)
+
+	if fd[0].FileName != "mypassword" {
+		t.Error("File name was not taken from the base of the path: ", fd[0].FileName)
+	}
+}
+
+func TestFileUploadFromDiskInfersMIMEType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grequests")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "upload.json")
+
+	if err := ioutil.WriteFile(fileName, []byte(`{"One":"Two"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	fd, err := FileUploadFromDisk(fileName)
+
+	if err != nil {
+		t.Fatal("Unable to open file: ", err)
+	}
+
+	defer closeFileUploads(fd)
+
+	if fd[0].ContentType != "application/json" {
+		t.Error("MIME type was not inferred from the file extension: ", fd[0].ContentType)
+	}
+}
+
+func TestFileUploadDetectContentTypeFromExtension(t *testing.T) {
+	f := FileUpload{FileName: "photo.png", FileContents: ioutil.NopCloser(strings.NewReader("not really a png"))}
+
+	contentType, _, err := f.detectContentType()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if contentType != "image/png" {
+		t.Error("MIME type was not guessed from the extension: ", contentType)
+	}
+}
+
+func TestFileUploadDetectContentTypeSniffed(t *testing.T) {
+	const contents = "<html><body>Hello</body></html>"
+
+	f := FileUpload{FileName: "no-extension", FileContents: ioutil.NopCloser(strings.NewReader(contents))}
+
+	contentType, rd, err := f.detectContentType()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if contentType != "text/html; charset=utf-8" {
+		t.Error("MIME type was not sniffed from the contents: ", contentType)
+	}
+
+	b, err := ioutil.ReadAll(rd)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != contents {
+		t.Error("Sniffed bytes were not replayed: ", string(b))
+	}
+}
+
+func TestFileUploadFieldNames(t *testing.T) {
+	ro := &RequestOptions{
+		Files: []FileUpload{
+			{FileName: "a.txt", FieldName: "attachments", FileContents: ioutil.NopCloser(strings.NewReader("a"))},
+			{FileName: "b.bin", FieldName: "attachments", ContentType: "application/x-custom",
+				FileContents: ioutil.NopCloser(strings.NewReader("b"))},
+			{FileName: "c.txt", FileContents: ioutil.NopCloser(strings.NewReader("c"))},
+		},
+		Data: map[string]string{"description": "three files"},
+	}
+
+	req, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro)
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal("Unable to parse multipart body: ", err)
+	}
+
+	attachments := req.MultipartForm.File["attachments"]
+
+	if len(attachments) != 2 || attachments[0].Filename != "a.txt" || attachments[1].Filename != "b.bin" {
+		t.Fatal("Files were not sent under their field name: ", req.MultipartForm.File)
+	}
+
+	if ct := attachments[1].Header.Get("Content-Type"); ct != "application/x-custom" {
+		t.Error("Content type was not used: ", ct)
+	}
+
+	if files := req.MultipartForm.File["file3"]; len(files) != 1 || files[0].Filename != "c.txt" {
+		t.Error("File without a field name was not given the default name: ", req.MultipartForm.File)
+	}
+
+	if req.MultipartForm.Value["description"][0] != "three files" {
+		t.Error("Form field was not sent: ", req.MultipartForm.Value)
+	}
+}
+
+func TestFileUploadDataList(t *testing.T) {
+	req, err := buildHTTPRequest("POST", "http://httpbin.org/post", &RequestOptions{
+		Files:    []FileUpload{{FileName: "a.txt", FileContents: ioutil.NopCloser(strings.NewReader("a"))}},
+		DataList: url.Values{"tag": {"b", "a"}},
+	})
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal("Unable to parse multipart body: ", err)
+	}
+
+	if tags := req.MultipartForm.Value["tag"]; len(tags) != 2 || tags[0] != "b" || tags[1] != "a" {
+		t.Error("Repeated form values were not sent: ", tags)
+	}
+}
+
+func TestFileUploadHeaders(t *testing.T) {
+	req, err := buildHTTPRequest("POST", "http://httpbin.org/post", &RequestOptions{
+		Files: []FileUpload{{FileName: "a.txt", FileContents: ioutil.NopCloser(strings.NewReader("a")),
+			Headers: map[string]string{"Content-ID": "<a>", "Content-Type": "ignored/type"}}},
+	})
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal("Unable to parse multipart body: ", err)
+	}
+
+	header := req.MultipartForm.File["file"][0].Header
+
+	if header.Get("Content-ID") != "<a>" {
+		t.Error("Part header was not sent: ", header)
+	}
+
+	if header.Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Error("Part header replaced the content type: ", header)
+	}
+}
+
+func TestFileUploadProgress(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "a.txt")
+
+	if err := ioutil.WriteFile(fileName, []byte(strings.Repeat("a", 10000)), 0644); err != nil {
+		t.Fatal("Unable to create file: ", err)
+	}
+
+	fd, err := os.Open(fileName)
+
+	if err != nil {
+		t.Fatal("Unable to open file: ", err)
+	}
+
+	var transferred, total int64
+	calls := 0
+
+	req, err := prepareRequest("POST", "http://httpbin.org/post", &RequestOptions{
+		Files: []FileUpload{{FileName: "a.txt", FileContents: fd}},
+		UploadProgress: func(sent, size int64) {
+			if sent < transferred {
+				t.Error("Progress went backwards: ", sent, transferred)
+			}
+
+			transferred, total = sent, size
+			calls++
+		},
+	})
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+
+	if err != nil {
+		t.Fatal("Unable to read body: ", err)
+	}
+
+	if calls == 0 || transferred != int64(len(body)) {
+		t.Errorf("Progress was not reported: %d calls, %d of %d bytes", calls, transferred, len(body))
+	}
+
+	if total != req.ContentLength || total != int64(len(body)) {
+		t.Error("Total was not the size of the body: ", total, req.ContentLength)
+	}
+}
+
+func TestFileUploadErrorClosesFiles(t *testing.T) {
+	files := []*closeRecorder{
+		newCloseRecorder(strings.NewReader("first")),
+		newCloseRecorder(strings.NewReader("second")),
+		newCloseRecorder(strings.NewReader("third")),
+	}
+
+	ro := &RequestOptions{Files: []FileUpload{
+		{FileName: "first.txt", FileContents: files[0]},
+		{FileName: "second.txt", FileContents: files[1], TransferEncoding: "uuencode"},
+		{FileName: "third.txt", FileContents: files[2]},
+	}}
+
+	if _, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro); err == nil {
+		t.Fatal("An unsupported transfer encoding was accepted")
+	}
+
+	for _, f := range files {
+		f.waitForClose(t)
+	}
+}
@@ -0,0 +1,71 @@
+package grequests
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// FormField is a single field within an ordered form body (see RequestOptions.FormFields)
+type FormField struct {
+	// Name is the name of the form field
+	Name string
+
+	// Value is the value of the form field. It is ignored if File is set
+	Value string
+
+	// File (if set) will be sent as a file within a multipart body
+	File *FileUpload
+}
+
+func createFormFieldsRequest(httpMethod, userURL string, ro *RequestOptions) (*http.Request, error) {
+	for _, field := range ro.FormFields {
+		if field.File != nil {
+			return createOrderedMultiPartRequest(httpMethod, userURL, ro)
+		}
+	}
+
+	req, err := http.NewRequest(httpMethod, userURL, strings.NewReader(encodeFormFields(ro.FormFields)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The content type must be set to a regular form
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return req, nil
+}
+
+func createOrderedMultiPartRequest(httpMethod, userURL string, ro *RequestOptions) (*http.Request, error) {
+	sections := make([]multipartSection, 0, len(ro.FormFields))
+
+	for _, field := range ro.FormFields {
+		if field.File == nil {
+			sections = append(sections, fieldSection(field.Name, field.Value))
+			continue
+		}
+
+		section, err := fileSection(field.Name, *field.File)
+
+		if err != nil {
+			closeSections(sections)
+			return nil, err
+		}
+
+		sections = append(sections, section)
+	}
+
+	return newMultipartRequest(httpMethod, userURL, sections, formDataContentType)
+}
+
+// encodeFormFields works like url.Values.Encode except that the order of the fields is preserved
+func encodeFormFields(fields []FormField) string {
+	encodedFields := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		encodedFields = append(encodedFields, url.QueryEscape(field.Name)+"="+url.QueryEscape(field.Value))
+	}
+
+	return strings.Join(encodedFields, "&")
+}
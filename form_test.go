@@ -0,0 +1,67 @@
+package grequests
+
+import (
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+func TestFormFieldsURLEncodedOrder(t *testing.T) {
+	ro := &RequestOptions{
+		FormFields: []FormField{{Name: "z", Value: "1"}, {Name: "a", Value: "2 3"}, {Name: "z", Value: "4"}},
+	}
+
+	req, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro)
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	b, _ := ioutil.ReadAll(req.Body)
+
+	if string(b) != "z=1&a=2+3&z=4" {
+		t.Error("Form fields were not encoded in order: ", string(b))
+	}
+
+	if req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		t.Error("Invalid Content-Type: ", req.Header.Get("Content-Type"))
+	}
+}
+
+func TestFormFieldsMultipartFileLast(t *testing.T) {
+	ro := &RequestOptions{
+		FormFields: []FormField{
+			{Name: "key", Value: "uploads/file.txt"},
+			{Name: "policy", Value: "signed"},
+			{Name: "file", File: &FileUpload{FileName: "file.txt", FileContents: ioutil.NopCloser(strings.NewReader("contents"))}},
+		},
+	}
+
+	req, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro)
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	if err != nil {
+		t.Fatal("Invalid Content-Type: ", err)
+	}
+
+	mr := multipart.NewReader(req.Body, params["boundary"])
+
+	for _, name := range []string{"key", "policy", "file"} {
+		part, err := mr.NextPart()
+
+		if err != nil {
+			t.Fatal("Unable to read part: ", err)
+		}
+
+		if part.FormName() != name {
+			t.Error("Form fields were not written in order: ", part.FormName(), name)
+		}
+	}
+}
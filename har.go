@@ -0,0 +1,302 @@
+package grequests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+)
+
+// HAR is an HTTP Archive (version 1.2) – the format used by browser devtools to export
+// the requests they have made
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the root of the HTTP Archive
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator is the application that created the HTTP Archive
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is a single request/response pair
+type HAREntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+}
+
+// HARRequest is the request of a HAREntry
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+// HARResponse is the response of a HAREntry
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+// HARNameValue is a header or a query string parameter
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARCookie is a cookie sent with a request or a response
+type HARCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+// HARPostData is the body of a request
+type HARPostData struct {
+	MimeType string     `json:"mimeType"`
+	Params   []HARParam `json:"params,omitempty"`
+	Text     string     `json:"text"`
+}
+
+// HARParam is a posted parameter (of a form body)
+type HARParam struct {
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// HARContent is the body of a response
+type HARContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// HARTimings is the amount of time (in milliseconds) spent in each phase of a request. -1 means
+// the phase does not apply
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// harSkippedHeaders are the request headers that are not replayed (they are either computed by
+// net/http, HTTP/2 pseudo headers or headers that we set ourselves)
+var harSkippedHeaders = map[string]struct{}{
+	"Host":              {},
+	"Content-Length":    {},
+	"Connection":        {},
+	"Accept-Encoding":   {},
+	"Transfer-Encoding": {},
+}
+
+// PreparedRequest is a fully specified request that can be sent (and sent again) as is
+type PreparedRequest struct {
+	// Method is the HTTP verb of the request
+	Method string
+
+	// URL is the complete URL of the request (including the query string)
+	URL string
+
+	// Header contains the headers of the request
+	Header http.Header
+
+	// Body is the body of the request (if any)
+	Body []byte
+}
+
+// LoadHAR reads an HTTP Archive and returns a PreparedRequest for every entry (in order)
+func LoadHAR(r io.Reader) ([]*PreparedRequest, error) {
+	har := &HAR{}
+
+	if err := json.NewDecoder(r).Decode(har); err != nil {
+		return nil, err
+	}
+
+	preparedRequests := make([]*PreparedRequest, 0, len(har.Log.Entries))
+
+	for _, entry := range har.Log.Entries {
+		preparedRequests = append(preparedRequests, entry.Request.preparedRequest())
+	}
+
+	return preparedRequests, nil
+}
+
+// LoadHARFile reads an HTTP Archive from the disk (see LoadHAR)
+func LoadHARFile(fileName string) ([]*PreparedRequest, error) {
+	fd, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer fd.Close()
+
+	return LoadHAR(fd)
+}
+
+// preparedRequest converts the HAR request into a PreparedRequest
+func (h HARRequest) preparedRequest() *PreparedRequest {
+	pr := &PreparedRequest{Method: h.Method, URL: h.URL, Header: http.Header{}}
+
+	for _, header := range h.Headers {
+		name := http.CanonicalHeaderKey(header.Name)
+
+		if _, skip := harSkippedHeaders[name]; skip || strings.HasPrefix(name, ":") {
+			continue
+		}
+
+		pr.Header.Add(name, header.Value)
+	}
+
+	// Browsers record the cookies within the Cookie header as well – only use the cookie list if it didn't
+	if pr.Header.Get("Cookie") == "" {
+		for _, c := range h.Cookies {
+			cookie := &http.Cookie{Name: c.Name, Value: c.Value}
+			pr.Header.Add("Cookie", cookie.String())
+		}
+	}
+
+	if h.PostData != nil {
+		pr.Body = []byte(h.PostData.Text)
+
+		if pr.Header.Get("Content-Type") == "" && h.PostData.MimeType != "" {
+			pr.Header.Set("Content-Type", h.PostData.MimeType)
+		}
+
+		if len(pr.Body) == 0 && len(h.PostData.Params) != 0 {
+			if mediaType, _, _ := mime.ParseMediaType(h.PostData.MimeType); mediaType == "multipart/form-data" {
+				var contentType string
+
+				pr.Body, contentType = harMultipartBody(h.PostData.Params)
+
+				// The body has a new boundary
+				pr.Header.Set("Content-Type", contentType)
+			} else {
+				fields := make([]FormField, 0, len(h.PostData.Params))
+
+				for _, param := range h.PostData.Params {
+					fields = append(fields, FormField{Name: param.Name, Value: param.Value})
+				}
+
+				pr.Body = []byte(encodeFormFields(fields))
+			}
+		}
+	}
+
+	return pr
+}
+
+// harMultipartBody encodes the params as a multipart/form-data body and returns it along with its Content-Type.
+// Params with a file name are sent as files (the value is used as the contents of the file)
+func harMultipartBody(params []HARParam) ([]byte, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for _, param := range params {
+		h := make(textproto.MIMEHeader)
+
+		if param.FileName != "" {
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+				escapeQuotes(param.Name), escapeQuotes(param.FileName)))
+		} else {
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(param.Name)))
+		}
+
+		if param.ContentType != "" {
+			h.Set("Content-Type", param.ContentType)
+		}
+
+		// Writing to a bytes.Buffer doesn't fail
+		part, _ := writer.CreatePart(h)
+		part.Write([]byte(param.Value))
+	}
+
+	writer.Close()
+
+	return body.Bytes(), formDataContentType(writer.Boundary())
+}
+
+// Do sends the prepared request
+func (pr *PreparedRequest) Do() (*Response, error) {
+	return pr.send(http.DefaultClient, nil)
+}
+
+func (pr *PreparedRequest) send(httpClient *http.Client, ro *RequestOptions) (*Response, error) {
+	if ro == nil {
+		ro = &RequestOptions{}
+	}
+
+	var body io.Reader
+
+	if len(pr.Body) != 0 {
+		body = bytes.NewReader(pr.Body)
+	}
+
+	req, err := http.NewRequest(pr.Method, pr.URL, body)
+
+	if err != nil {
+		return buildResponse(nil, err)
+	}
+
+	for key, values := range pr.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	req.Header.Set("Accept-Encoding", acceptEncoding())
+
+	if err := runBeforeRequestHooks(req, ro.BeforeRequest); err != nil {
+		return buildResponse(nil, err)
+	}
+
+	started := time.Now()
+
+	resp, err := sendRequest(addRedirectFunctionality(httpClient, ro), req, ro)
+
+	if ro.HARRecorder != nil {
+		ro.HARRecorder.record(req, resp, started)
+	}
+
+	return runAfterResponseHooks(resp, err, ro.AfterResponse)
+}
@@ -0,0 +1,309 @@
+package grequests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Default value for HARRecorder MaxBodySize
+const defaultHARMaxBodySize = 1 << 20
+
+// HARRecorder records every request (and its response) sent with it so they can be exported
+// as an HTTP Archive. Set it as the HARRecorder of a Session (or of RequestOptions) to start
+// recording. A HARRecorder is safe for concurrent use
+type HARRecorder struct {
+	// MaxBodySize is the maximum amount of bytes of each request and response body that are
+	// recorded (the rest is dropped). The default is 1MB, a negative value disables recording bodies
+	MaxBodySize int64
+
+	mu      sync.Mutex
+	entries []*HAREntry
+}
+
+// NewHARRecorder returns a HARRecorder that records bodies up to the default size
+func NewHARRecorder() *HARRecorder {
+	return &HARRecorder{}
+}
+
+func (h *HARRecorder) maxBodySize() int64 {
+	if h.MaxBodySize == 0 {
+		return defaultHARMaxBodySize
+	}
+
+	if h.MaxBodySize < 0 {
+		return 0
+	}
+
+	return h.MaxBodySize
+}
+
+// HAR returns an HTTP Archive of the requests that have been recorded so far (in the order they were sent)
+func (h *HARRecorder) HAR() *HAR {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	har := &HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "grequests", Version: strings.TrimPrefix(localUserAgent, "GRequests ")},
+		Entries: make([]HAREntry, 0, len(h.entries)),
+	}}
+
+	for _, entry := range h.entries {
+		har.Log.Entries = append(har.Log.Entries, *entry)
+	}
+
+	return har
+}
+
+// Export writes the HTTP Archive of the requests that have been recorded so far to w
+func (h *HARRecorder) Export(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(h.HAR())
+}
+
+// ExportFile writes the HTTP Archive of the requests that have been recorded so far to the disk
+func (h *HARRecorder) ExportFile(fileName string) error {
+	fd, err := os.Create(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	if err := h.Export(fd); err != nil {
+		fd.Close()
+		return err
+	}
+
+	return fd.Close()
+}
+
+// Reset drops every entry that has been recorded
+func (h *HARRecorder) Reset() {
+	h.mu.Lock()
+	h.entries = nil
+	h.mu.Unlock()
+}
+
+// record adds an entry for the request. The content of the response is recorded as the body is read
+func (h *HARRecorder) record(req *http.Request, resp *Response, started time.Time) {
+	entry := &HAREntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            harMilliseconds(resp.Timings.Total),
+		Request:         h.harRequest(req),
+		Response:        HARResponse{Cookies: []HARCookie{}, Headers: []HARNameValue{}, HeadersSize: -1, BodySize: -1},
+	}
+
+	entry.Timings = harTimings(resp.Timings)
+
+	if resp.RawResponse != nil {
+		entry.Response = HARResponse{
+			Status:      resp.StatusCode,
+			StatusText:  strings.TrimSpace(strings.TrimPrefix(resp.RawResponse.Status, strconv.Itoa(resp.StatusCode))),
+			HTTPVersion: resp.RawResponse.Proto,
+			Cookies:     harCookies(resp.RawResponse.Cookies()),
+			Headers:     harHeaders(resp.RawResponse.Header),
+			Content:     HARContent{MimeType: resp.RawResponse.Header.Get("Content-Type")},
+			RedirectURL: resp.RawResponse.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    resp.RawResponse.ContentLength,
+		}
+
+		resp.RawResponse.Body = &harBody{ReadCloser: resp.RawResponse.Body, recorder: h, entry: entry, response: resp}
+	}
+
+	h.mu.Lock()
+	h.entries = append(h.entries, entry)
+	h.mu.Unlock()
+}
+
+// harRequest converts the request into a HARRequest (reading the body again if possible)
+func (h *HARRecorder) harRequest(req *http.Request) HARRequest {
+	harReq := HARRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     harCookies(req.Cookies()),
+		Headers:     harHeaders(req.Header),
+		QueryString: []HARNameValue{},
+		HeadersSize: -1,
+		BodySize:    req.ContentLength,
+	}
+
+	query := req.URL.Query()
+
+	for _, key := range sortedValueKeys(query) {
+		for _, value := range query[key] {
+			harReq.QueryString = append(harReq.QueryString, HARNameValue{Name: key, Value: value})
+		}
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return harReq
+	}
+
+	harReq.PostData = &HARPostData{MimeType: req.Header.Get("Content-Type")}
+
+	if req.GetBody == nil {
+		return harReq
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return harReq
+	}
+
+	defer body.Close()
+
+	text, _ := ioutil.ReadAll(io.LimitReader(body, h.maxBodySize()))
+
+	harReq.PostData.Text = string(text)
+
+	return harReq
+}
+
+// harBody records the content of the response as it is read
+type harBody struct {
+	io.ReadCloser
+	recorder *HARRecorder
+	entry    *HAREntry
+	response *Response
+	content  []byte
+	size     int64
+	once     sync.Once
+}
+
+func (b *harBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.size += int64(n)
+
+	if remaining := b.recorder.maxBodySize() - int64(len(b.content)); remaining > 0 {
+		if int64(n) < remaining {
+			remaining = int64(n)
+		}
+
+		b.content = append(b.content, p[:remaining]...)
+	}
+
+	if err == io.EOF {
+		b.once.Do(b.finish)
+	}
+
+	return n, err
+}
+
+func (b *harBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.finish)
+	return err
+}
+
+// finish stores the content (and the final timings) within the entry
+func (b *harBody) finish() {
+	b.recorder.mu.Lock()
+	defer b.recorder.mu.Unlock()
+
+	b.entry.Response.Content.Size = b.size
+
+	if utf8.Valid(b.content) {
+		b.entry.Response.Content.Text = string(b.content)
+	} else {
+		b.entry.Response.Content.Text = base64.StdEncoding.EncodeToString(b.content)
+		b.entry.Response.Content.Encoding = "base64"
+	}
+
+	// The total time of the response now includes reading the body
+	b.entry.Time = harMilliseconds(b.response.Timings.Total)
+	b.entry.Timings.Receive = harMilliseconds(b.response.Timings.Total - b.response.Timings.TTFB)
+}
+
+// harTimings converts the timings of a response into HARTimings
+func harTimings(timings Timings) HARTimings {
+	harTimings := HARTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1}
+
+	wait := timings.TTFB
+
+	if timings.DNSLookup > 0 {
+		harTimings.DNS = harMilliseconds(timings.DNSLookup)
+		wait -= timings.DNSLookup
+	}
+
+	// The connect time of a HAR includes the TLS handshake
+	if timings.Connect > 0 {
+		harTimings.Connect = harMilliseconds(timings.Connect + timings.TLSHandshake)
+		wait -= timings.Connect + timings.TLSHandshake
+	}
+
+	if timings.TLSHandshake > 0 {
+		harTimings.SSL = harMilliseconds(timings.TLSHandshake)
+	}
+
+	if wait < 0 {
+		wait = 0
+	}
+
+	harTimings.Wait = harMilliseconds(wait)
+	harTimings.Receive = harMilliseconds(timings.Total - timings.TTFB)
+
+	return harTimings
+}
+
+// harMilliseconds converts the duration into (fractional) milliseconds
+func harMilliseconds(d time.Duration) float64 {
+	if d < 0 {
+		return 0
+	}
+
+	return float64(d) / float64(time.Millisecond)
+}
+
+// harHeaders converts the headers into a (sorted) list of HARNameValue
+func harHeaders(header http.Header) []HARNameValue {
+	keys := make([]string, 0, len(header))
+
+	for key := range header {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	headers := []HARNameValue{}
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			headers = append(headers, HARNameValue{Name: key, Value: value})
+		}
+	}
+
+	return headers
+}
+
+// harCookies converts the cookies into a list of HARCookie
+func harCookies(cookies []*http.Cookie) []HARCookie {
+	harCookies := make([]HARCookie, 0, len(cookies))
+
+	for _, c := range cookies {
+		harCookie := HARCookie{Name: c.Name, Value: c.Value, Path: c.Path, Domain: c.Domain, HTTPOnly: c.HttpOnly, Secure: c.Secure}
+
+		if !c.Expires.IsZero() {
+			harCookie.Expires = c.Expires.Format(time.RFC3339)
+		}
+
+		harCookies = append(harCookies, harCookie)
+	}
+
+	return harCookies
+}
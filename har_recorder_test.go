@@ -0,0 +1,118 @@
+package grequests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestHARRecorder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "1234"})
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("Hello " + r.Method))
+	}))
+	defer ts.Close()
+
+	recorder := NewHARRecorder()
+
+	session := NewSession(nil)
+	session.HARRecorder = recorder
+
+	resp, err := session.Post(ts.URL+"/users?b=2&a=1", &RequestOptions{JSON: map[string]string{"name": "levi"}})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if resp.String() != "Hello POST" {
+		t.Error("Recording changed the body: ", resp.String())
+	}
+
+	if _, err := session.Get(ts.URL, nil); err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	har := recorder.HAR()
+
+	if har.Log.Version != "1.2" || har.Log.Creator.Name != "grequests" || len(har.Log.Entries) != 2 {
+		t.Fatalf("Unexpected HAR: %+v", har.Log)
+	}
+
+	entry := har.Log.Entries[0]
+
+	if entry.Request.Method != "POST" || entry.Request.URL != ts.URL+"/users?b=2&a=1" ||
+		entry.Request.PostData == nil || entry.Request.PostData.Text != "{\"name\":\"levi\"}\n" ||
+		entry.Request.PostData.MimeType != "application/json" {
+		t.Errorf("Unexpected request: %+v", entry.Request)
+	}
+
+	if len(entry.Request.QueryString) != 2 || entry.Request.QueryString[0].Name != "a" {
+		t.Error("Unexpected query string: ", entry.Request.QueryString)
+	}
+
+	if entry.Response.Status != http.StatusCreated || entry.Response.StatusText != "Created" ||
+		entry.Response.Content.Text != "Hello POST" || entry.Response.Content.MimeType != "text/plain" ||
+		len(entry.Response.Cookies) != 1 || entry.Response.Cookies[0].Value != "1234" {
+		t.Errorf("Unexpected response: %+v", entry.Response)
+	}
+
+	if entry.Time <= 0 || entry.Timings.Connect <= 0 || entry.Timings.SSL != -1 {
+		t.Errorf("Unexpected timings: %v %+v", entry.Time, entry.Timings)
+	}
+
+	// The second request wasn't read by us so the content is recorded once the body is closed
+	if har.Log.Entries[1].Request.Method != "GET" || har.Log.Entries[1].Request.PostData != nil {
+		t.Errorf("Unexpected request: %+v", har.Log.Entries[1].Request)
+	}
+
+	fileName := filepath.Join(t.TempDir(), "session.har")
+
+	if err := recorder.ExportFile(fileName); err != nil {
+		t.Fatal("Unable to export HAR: ", err)
+	}
+
+	requests, err := LoadHARFile(fileName)
+
+	if err != nil {
+		t.Fatal("Unable to load the exported HAR: ", err)
+	}
+
+	if len(requests) != 2 || string(requests[0].Body) != "{\"name\":\"levi\"}\n" {
+		t.Errorf("Unexpected replayed requests: %+v", requests)
+	}
+
+	recorder.Reset()
+
+	if len(recorder.HAR().Log.Entries) != 0 {
+		t.Error("Reset didn't drop the entries")
+	}
+}
+
+func TestHARRecorderMaxBodySize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte{0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa})
+	}))
+	defer ts.Close()
+
+	recorder := &HARRecorder{MaxBodySize: 4}
+
+	resp, err := Get(ts.URL, &RequestOptions{HARRecorder: recorder})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if !bytes.Equal(resp.Bytes(), []byte{0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa}) {
+		t.Error("Recording changed the body: ", resp.Bytes())
+	}
+
+	content := recorder.HAR().Log.Entries[0].Response.Content
+
+	if content.Size != 6 || content.Encoding != "base64" || content.Text != "//79/A==" {
+		t.Errorf("Unexpected content: %+v", content)
+	}
+}
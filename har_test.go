@@ -0,0 +1,140 @@
+package grequests
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testHAR = `{
+  "log": {
+    "version": "1.2",
+    "creator": {"name": "Browser", "version": "1"},
+    "entries": [
+      {
+        "request": {
+          "method": "POST",
+          "url": "{{URL}}/login?next=%2F",
+          "httpVersion": "HTTP/2",
+          "headers": [
+            {"name": ":authority", "value": "example.com"},
+            {"name": "content-type", "value": "application/json"},
+            {"name": "content-length", "value": "17"},
+            {"name": "x-csrf-token", "value": "abc"}
+          ],
+          "cookies": [{"name": "session", "value": "1234"}],
+          "postData": {"mimeType": "application/json", "text": "{\"user\":\"levi\"}"}
+        }
+      },
+      {
+        "request": {
+          "method": "PUT",
+          "url": "{{URL}}/form",
+          "headers": [],
+          "cookies": [],
+          "postData": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "b", "value": "2"}, {"name": "a", "value": "1"}]}
+        }
+      }
+    ]
+  }
+}`
+
+func TestLoadHARAndReplay(t *testing.T) {
+	var received []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		cookie, _ := r.Cookie("session")
+
+		if cookie == nil {
+			cookie = &http.Cookie{}
+		}
+
+		received = append(received, strings.Join([]string{
+			r.Method, r.URL.RequestURI(), r.Header.Get("Content-Type"), r.Header.Get("X-Csrf-Token"), cookie.Value, string(body),
+		}, " "))
+	}))
+	defer ts.Close()
+
+	preparedRequests, err := LoadHAR(strings.NewReader(strings.Replace(testHAR, "{{URL}}", ts.URL, -1)))
+
+	if err != nil {
+		t.Fatal("Unable to load HAR: ", err)
+	}
+
+	session := NewSession(nil)
+
+	for _, pr := range preparedRequests {
+		if _, err := session.Do(pr); err != nil {
+			t.Fatal("Unable to replay request: ", err)
+		}
+	}
+
+	expected := []string{
+		`POST /login?next=%2F application/json abc 1234 {"user":"levi"}`,
+		`PUT /form application/x-www-form-urlencoded   b=2&a=1`,
+	}
+
+	if len(received) != len(expected) {
+		t.Fatal("Not every request was replayed: ", received)
+	}
+
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Error("Request was not replayed properly: ", received[i])
+		}
+	}
+}
+
+func TestLoadHARMultipartParams(t *testing.T) {
+	const multipartHAR = `{"log": {"entries": [{"request": {
+	  "method": "POST",
+	  "url": "http://example.com/upload",
+	  "headers": [{"name": "Content-Type", "value": "multipart/form-data; boundary=----recorded"}],
+	  "postData": {
+	    "mimeType": "multipart/form-data; boundary=----recorded",
+	    "params": [
+	      {"name": "title", "value": "Hello"},
+	      {"name": "file", "value": "contents", "fileName": "hello.txt", "contentType": "text/plain"}
+	    ]
+	  }
+	}}]}}`
+
+	preparedRequests, err := LoadHAR(strings.NewReader(multipartHAR))
+
+	if err != nil {
+		t.Fatal("Unable to load HAR: ", err)
+	}
+
+	req, err := http.NewRequest("POST", "http://example.com/upload", bytes.NewReader(preparedRequests[0].Body))
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	req.Header = preparedRequests[0].Header
+
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal("Body is not a valid multipart form: ", err)
+	}
+
+	if req.FormValue("title") != "Hello" {
+		t.Error("Field was not sent: ", req.MultipartForm.Value)
+	}
+
+	files := req.MultipartForm.File["file"]
+
+	if len(files) != 1 || files[0].Filename != "hello.txt" || files[0].Header.Get("Content-Type") != "text/plain" {
+		t.Fatal("File was not sent: ", files)
+	}
+
+	fd, _ := files[0].Open()
+	defer fd.Close()
+
+	if b, _ := ioutil.ReadAll(fd); string(b) != "contents" {
+		t.Error("File contents are invalid: ", string(b))
+	}
+}
@@ -0,0 +1,31 @@
+package grequests
+
+import "net/http"
+
+// runBeforeRequestHooks calls every BeforeRequest hook (in order) stopping at the first error
+func runBeforeRequestHooks(req *http.Request, hooks []func(*http.Request) error) error {
+	for _, hook := range hooks {
+		if err := hook(req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runAfterResponseHooks calls every AfterResponse hook (in order) with a successful response. If
+// a hook returns an error the body of the response is closed and the error is returned instead
+func runAfterResponseHooks(resp *Response, err error, hooks []func(*http.Response) error) (*Response, error) {
+	if err != nil {
+		return resp, err
+	}
+
+	for _, hook := range hooks {
+		if err := hook(resp.RawResponse); err != nil {
+			resp.Close()
+			return buildResponse(nil, err)
+		}
+	}
+
+	return resp, nil
+}
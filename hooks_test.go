@@ -0,0 +1,133 @@
+package grequests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHooksAreCalledInOrder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer ts.Close()
+
+	var calls []string
+
+	session := NewSession(nil)
+	session.BeforeRequest = []func(*http.Request) error{func(req *http.Request) error {
+		calls = append(calls, "session before")
+		req.Header.Set("Authorization", "Bearer token")
+		return nil
+	}}
+	session.AfterResponse = []func(*http.Response) error{func(*http.Response) error {
+		calls = append(calls, "session after")
+		return nil
+	}}
+
+	ro := &RequestOptions{
+		BeforeRequest: []func(*http.Request) error{func(*http.Request) error {
+			calls = append(calls, "request before")
+			return nil
+		}},
+		AfterResponse: []func(*http.Response) error{func(resp *http.Response) error {
+			calls = append(calls, "request after")
+
+			if resp.StatusCode != http.StatusOK {
+				t.Error("Hook received an invalid response: ", resp.StatusCode)
+			}
+
+			return nil
+		}},
+	}
+
+	resp, err := session.Get(ts.URL, ro)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "Bearer token" {
+		t.Error("BeforeRequest hook did not modify the request: ", resp.String())
+	}
+
+	expected := []string{"session before", "request before", "session after", "request after"}
+
+	if len(calls) != len(expected) {
+		t.Fatal("Unexpected hook calls: ", calls)
+	}
+
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Fatal("Unexpected hook calls: ", calls)
+		}
+	}
+
+	if len(ro.BeforeRequest) != 1 || len(ro.AfterResponse) != 1 {
+		t.Error("Session hooks were added to the request options")
+	}
+}
+
+func TestBeforeRequestHookError(t *testing.T) {
+	hookErr := errors.New("no token")
+
+	resp, err := Get("http://127.0.0.1:1/", &RequestOptions{
+		BeforeRequest: []func(*http.Request) error{func(*http.Request) error { return hookErr }},
+	})
+
+	if err != hookErr || resp.Error != hookErr {
+		t.Error("Expected the error of the hook, got: ", err)
+	}
+}
+
+func TestAfterResponseHookError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	hookErr := errors.New("unauthorized")
+
+	_, err := Get(ts.URL, &RequestOptions{
+		AfterResponse: []func(*http.Response) error{func(resp *http.Response) error {
+			if resp.StatusCode == http.StatusUnauthorized {
+				return hookErr
+			}
+			return nil
+		}},
+	})
+
+	if err != hookErr {
+		t.Error("Expected the error of the hook, got: ", err)
+	}
+}
+
+type failingRateLimiter struct{}
+
+func (failingRateLimiter) Wait(ctx context.Context, req *http.Request) error {
+	return errors.New("rate limited")
+}
+
+func TestUnsentUploadsAreClosed(t *testing.T) {
+	failingHook := func(*http.Request) error { return errors.New("hook failed") }
+
+	tests := map[string]*RequestOptions{
+		"hook":         {BeforeRequest: []func(*http.Request) error{failingHook}},
+		"rate limiter": {RateLimiter: failingRateLimiter{}},
+		"authorizer":   {Authorizer: AuthorizerFunc(failingHook)},
+	}
+
+	for name, ro := range tests {
+		upload := newCloseRecorder(strings.NewReader("contents"))
+		ro.Files = []FileUpload{{FileName: "file.txt", FileContents: upload}}
+
+		if _, err := Post("http://127.0.0.1:1", ro); err == nil {
+			t.Fatal(name, ": request did not fail")
+		}
+
+		upload.waitForClose(t)
+	}
+}
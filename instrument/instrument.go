@@ -0,0 +1,161 @@
+// Package instrument records Prometheus metrics and OpenTelemetry client spans for the requests
+// sent by grequests. The metrics are the amount of requests (by host, method and status code) and
+// a latency histogram (by host and method). Every request is wrapped in a client span and the
+// trace context is propagated within the headers of the request (e.g. traceparent). Set Wrap as
+// the WrapTransport of the RequestOptions:
+//
+//	instrumentation, err := instrument.New(instrument.Options{Registerer: prometheus.DefaultRegisterer})
+//
+//	session := grequests.NewSession(&grequests.RequestOptions{WrapTransport: instrumentation.Wrap})
+package instrument
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer that creates the client spans
+const tracerName = "github.com/levigross/grequests/instrument"
+
+// Options configures the metrics and spans that are recorded
+type Options struct {
+	// Registerer (if set) is where the metrics are registered. No metrics are recorded without one
+	Registerer prometheus.Registerer
+
+	// Namespace is the prefix of the names of the metrics. The default is "grequests"
+	Namespace string
+
+	// Buckets are the buckets (in seconds) of the latency histogram. The default is prometheus.DefBuckets
+	Buckets []float64
+
+	// TracerProvider creates the tracer of the client spans. The default is the global TracerProvider
+	TracerProvider trace.TracerProvider
+
+	// Propagator injects the trace context into the headers of the request. The default is the
+	// global TextMapPropagator
+	Propagator propagation.TextMapPropagator
+}
+
+// Instrumentation records the metrics and spans of the requests sent through the transports it wraps.
+// An Instrumentation is safe for concurrent use (and may wrap several transports)
+type Instrumentation struct {
+	requests   *prometheus.CounterVec
+	latency    *prometheus.HistogramVec
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// New returns an Instrumentation that records metrics (registering them with the Registerer) and spans
+func New(opts Options) (*Instrumentation, error) {
+	if opts.Namespace == "" {
+		opts.Namespace = "grequests"
+	}
+
+	if opts.Buckets == nil {
+		opts.Buckets = prometheus.DefBuckets
+	}
+
+	if opts.TracerProvider == nil {
+		opts.TracerProvider = otel.GetTracerProvider()
+	}
+
+	if opts.Propagator == nil {
+		opts.Propagator = otel.GetTextMapPropagator()
+	}
+
+	i := &Instrumentation{
+		tracer:     opts.TracerProvider.Tracer(tracerName),
+		propagator: opts.Propagator,
+	}
+
+	if opts.Registerer == nil {
+		return i, nil
+	}
+
+	i.requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: opts.Namespace,
+		Subsystem: "client",
+		Name:      "requests_total",
+		Help:      "The amount of HTTP requests sent by host, method and status code (\"error\" if the request failed).",
+	}, []string{"host", "method", "code"})
+
+	i.latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: opts.Namespace,
+		Subsystem: "client",
+		Name:      "request_duration_seconds",
+		Help:      "The time until the response headers of an HTTP request were received by host and method.",
+		Buckets:   opts.Buckets,
+	}, []string{"host", "method"})
+
+	for _, collector := range []prometheus.Collector{i.requests, i.latency} {
+		if err := opts.Registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// Wrap returns a transport that records the requests sent through next. Its signature matches
+// the WrapTransport option of grequests
+func (i *Instrumentation) Wrap(next http.RoundTripper) http.RoundTripper {
+	return &transport{next: next, instrumentation: i}
+}
+
+// transport is an http.RoundTripper that records the metrics and span of each request
+type transport struct {
+	next            http.RoundTripper
+	instrumentation *Instrumentation
+}
+
+// RoundTrip sends the request within a client span. The span (and the latency) ends once the
+// response headers have been received
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := t.instrumentation
+	started := time.Now()
+
+	ctx, span := i.tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.Redacted()),
+			attribute.String("server.address", req.URL.Hostname()),
+		))
+
+	defer span.End()
+
+	// A RoundTripper must not modify the request so the trace context is added to a copy
+	req = req.Clone(ctx)
+	i.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+
+	code := "error"
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		code = strconv.Itoa(resp.StatusCode)
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+
+	if i.requests != nil {
+		i.requests.WithLabelValues(req.URL.Host, req.Method, code).Inc()
+		i.latency.WithLabelValues(req.URL.Host, req.Method).Observe(time.Since(started).Seconds())
+	}
+
+	return resp, err
+}
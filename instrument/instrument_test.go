@@ -0,0 +1,121 @@
+package instrument
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/levigross/grequests"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInstrumentation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Write([]byte(r.Header.Get("Traceparent")))
+	}))
+	defer ts.Close()
+
+	registry := prometheus.NewRegistry()
+	spans := tracetest.NewSpanRecorder()
+
+	instrumentation, err := New(Options{
+		Registerer:     registry,
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		Propagator:     propagation.TraceContext{},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ro := &grequests.RequestOptions{WrapTransport: instrumentation.Wrap}
+
+	resp, err := grequests.Get(ts.URL, ro)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ended := spans.Ended()
+
+	if len(ended) != 1 || ended[0].SpanKind() != trace.SpanKindClient || ended[0].Name() != "HTTP GET" {
+		t.Fatal("Unexpected spans: ", ended)
+	}
+
+	// The server receives the trace context of the client span
+	if !strings.Contains(resp.String(), ended[0].SpanContext().TraceID().String()) {
+		t.Error("The trace context was not propagated: ", resp.String())
+	}
+
+	if _, err := grequests.Post(ts.URL+"/missing", ro); err != nil {
+		t.Fatal(err)
+	}
+
+	if ended = spans.Ended(); ended[1].Status().Code != codes.Error {
+		t.Error("Expected a 404 to be an error: ", ended[1].Status())
+	}
+
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	if count := testutil.ToFloat64(instrumentation.requests.WithLabelValues(host, "GET", "200")); count != 1 {
+		t.Error("Unexpected amount of GET requests: ", count)
+	}
+
+	if count := testutil.ToFloat64(instrumentation.requests.WithLabelValues(host, "POST", "404")); count != 1 {
+		t.Error("Unexpected amount of POST requests: ", count)
+	}
+
+	if count := testutil.CollectAndCount(registry, "grequests_client_request_duration_seconds"); count != 2 {
+		t.Error("Unexpected amount of latency histograms: ", count)
+	}
+
+	// Registering the metrics twice fails
+	if _, err := New(Options{Registerer: registry}); err == nil {
+		t.Error("Expected the metrics to already be registered")
+	}
+}
+
+func TestInstrumentationError(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+
+	instrumentation, err := New(Options{TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := errors.New("connection refused")
+
+	transport := instrumentation.Wrap(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, failed
+	}))
+
+	req, _ := http.NewRequest("GET", "http://127.0.0.1/", nil)
+
+	if _, err := transport.RoundTrip(req); err != failed {
+		t.Error("Expected the error of the transport, got: ", err)
+	}
+
+	if ended := spans.Ended(); len(ended) != 1 || ended[0].Status().Code != codes.Error || len(ended[0].Events()) != 1 {
+		t.Error("Expected the error to be recorded: ", ended)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,187 @@
+package grequests
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Default value for LogOptions MaxBodySize
+const defaultLogMaxBodySize = 4096
+
+// redactedValue replaces the value of redacted headers
+const redactedValue = "[REDACTED]"
+
+// defaultRedactedHeaders are the headers that are redacted unless LogOptions.RedactHeaders is set
+var defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Logger logs the requests that are sent (and their responses). *log.Logger satisfies Logger
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// LogOptions controls what is logged by the Logger of a request. By default only the method,
+// URL, status and duration of the request are logged
+type LogOptions struct {
+	// Headers logs the headers of the request and the response
+	Headers bool
+
+	// Bodies logs the bodies of the request and the response (up to MaxBodySize bytes).
+	// The start of the response body is read before the response is returned
+	Bodies bool
+
+	// MaxBodySize is the maximum amount of bytes of a body that is logged. The default is 4KB
+	MaxBodySize int64
+
+	// RedactHeaders are the headers whose values are replaced with "[REDACTED]". The default
+	// is Authorization, Proxy-Authorization, Cookie and Set-Cookie
+	RedactHeaders []string
+}
+
+func (lo LogOptions) maxBodySize() int64 {
+	if lo.MaxBodySize <= 0 {
+		return defaultLogMaxBodySize
+	}
+
+	return lo.MaxBodySize
+}
+
+// isRedacted reports if the value of the header must not be logged
+func (lo LogOptions) isRedacted(key string) bool {
+	redactHeaders := lo.RedactHeaders
+
+	if redactHeaders == nil {
+		redactHeaders = defaultRedactedHeaders
+	}
+
+	for _, redacted := range redactHeaders {
+		if strings.EqualFold(redacted, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// logRequest logs the request that is about to be sent
+func logRequest(ro *RequestOptions, req *http.Request) {
+	ro.Logger.Printf("grequests: --> %s %s", req.Method, req.URL.Redacted())
+
+	if ro.LogOptions.Headers {
+		logHeaders(ro, "-->", req.Header)
+	}
+
+	if !ro.LogOptions.Bodies || req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+
+	if req.GetBody == nil {
+		ro.Logger.Printf("grequests: --> [body can only be read once]")
+		return
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		ro.Logger.Printf("grequests: --> [unable to read body: %v]", err)
+		return
+	}
+
+	defer body.Close()
+
+	logBody(ro, "-->", body)
+}
+
+// logResponse logs the response (or the error) of the request
+func logResponse(ro *RequestOptions, req *http.Request, resp *Response, err error, started time.Time) {
+	duration := time.Since(started).Round(time.Microsecond)
+
+	if err != nil {
+		ro.Logger.Printf("grequests: <-- %s %s error: %v (%s)", req.Method, req.URL.Redacted(), err, duration)
+		return
+	}
+
+	ro.Logger.Printf("grequests: <-- %s %s %s (%s)", resp.RawResponse.Status, req.Method, req.URL.Redacted(), duration)
+
+	if ro.LogOptions.Headers {
+		logHeaders(ro, "<--", resp.RawResponse.Header)
+	}
+
+	if !ro.LogOptions.Bodies {
+		return
+	}
+
+	// The start of the body is read so it can be logged and then replayed to the user
+	start, readErr := ioutil.ReadAll(io.LimitReader(resp.RawResponse.Body, ro.LogOptions.maxBodySize()+1))
+
+	body := resp.RawResponse.Body
+
+	resp.RawResponse.Body = readCloser{
+		Reader: io.MultiReader(bytes.NewReader(start), &errorReader{Reader: body, err: readErr}),
+		Closer: body,
+	}
+
+	logBody(ro, "<--", bytes.NewReader(start))
+}
+
+// logHeaders logs the headers (sorted by name) redacting the sensitive ones
+func logHeaders(ro *RequestOptions, direction string, header http.Header) {
+	keys := make([]string, 0, len(header))
+
+	for key := range header {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			if ro.LogOptions.isRedacted(key) {
+				value = redactedValue
+			}
+
+			ro.Logger.Printf("grequests: %s %s: %s", direction, key, value)
+		}
+	}
+}
+
+// logBody logs the body up to MaxBodySize bytes
+func logBody(ro *RequestOptions, direction string, body io.Reader) {
+	maxBodySize := ro.LogOptions.maxBodySize()
+
+	contents, err := ioutil.ReadAll(io.LimitReader(body, maxBodySize+1))
+
+	if err != nil {
+		ro.Logger.Printf("grequests: %s [unable to read body: %v]", direction, err)
+		return
+	}
+
+	if len(contents) == 0 {
+		return
+	}
+
+	if int64(len(contents)) > maxBodySize {
+		ro.Logger.Printf("grequests: %s %s [truncated]", direction, contents[:maxBodySize])
+		return
+	}
+
+	ro.Logger.Printf("grequests: %s %s", direction, contents)
+}
+
+// errorReader returns err (if set) instead of reading from the reader
+type errorReader struct {
+	io.Reader
+	err error
+}
+
+func (e *errorReader) Read(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	return e.Reader.Read(p)
+}
@@ -0,0 +1,98 @@
+package grequests
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestLoggerRedactsHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret-cookie"})
+		w.Header().Set("X-Request-Id", "42")
+		w.Write([]byte("0123456789"))
+	}))
+	defer ts.Close()
+
+	logger := &recordingLogger{}
+
+	resp, err := Post(ts.URL, &RequestOptions{
+		Logger:      logger,
+		LogOptions:  LogOptions{Headers: true, Bodies: true, MaxBodySize: 5},
+		BearerToken: "secret-token",
+		JSON:        map[string]string{"a": "b"},
+	})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if resp.String() != "0123456789" {
+		t.Error("Logging changed the body: ", resp.String())
+	}
+
+	output := strings.Join(logger.lines, "\n")
+
+	if strings.Contains(output, "secret-token") || strings.Contains(output, "secret-cookie") {
+		t.Error("Sensitive headers were logged: ", output)
+	}
+
+	for _, expected := range []string{
+		"grequests: --> POST " + ts.URL,
+		"grequests: --> Authorization: [REDACTED]",
+		"grequests: --> {\"a\": [truncated]",
+		"grequests: <-- 200 OK POST " + ts.URL,
+		"grequests: <-- Set-Cookie: [REDACTED]",
+		"grequests: <-- X-Request-Id: 42",
+		"grequests: <-- 01234 [truncated]",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q to be logged:\n%s", expected, output)
+		}
+	}
+}
+
+func TestLoggerDefaultsAndErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	buf := &bytes.Buffer{}
+	logger := log.New(buf, "", 0)
+
+	if _, err := Get(ts.URL, &RequestOptions{Logger: logger, Headers: map[string]string{"X-Secret": "1"}}); err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if len(lines) != 2 || lines[0] != "grequests: --> GET "+ts.URL ||
+		!strings.HasPrefix(lines[1], "grequests: <-- 404 Not Found GET "+ts.URL+" (") {
+		t.Errorf("Unexpected log lines: %q", lines)
+	}
+
+	buf.Reset()
+
+	ts.Close()
+
+	if _, err := Get(ts.URL, &RequestOptions{Logger: logger}); err == nil {
+		t.Fatal("Request to a closed server succeeded")
+	}
+
+	if !strings.Contains(buf.String(), "grequests: <-- GET "+ts.URL+" error: ") {
+		t.Error("Error was not logged: ", buf.String())
+	}
+}
@@ -0,0 +1,169 @@
+package grequests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Default value for the polling interval of a long running operation (when the server doesn't
+// provide a Retry-After header)
+const defaultOperationPollInterval = time.Second
+
+var (
+	// ErrOperationFailed is the error returned when a long running operation finished with a
+	// failed (or cancelled) status
+	ErrOperationFailed = errors.New("grequests: Long running operation failed")
+
+	// operationSucceeded and operationFailed are the (lower case) values of the "status" field
+	// that indicate that the operation has finished
+	operationSucceeded = map[string]struct{}{"succeeded": {}, "success": {}, "completed": {}, "done": {}}
+	operationFailed    = map[string]struct{}{"failed": {}, "canceled": {}, "cancelled": {}, "error": {}}
+)
+
+// WaitForOperation follows a long running operation that was started by resp (the common
+// 202 Accepted + Location/Operation-Location pattern). The status URL is polled (respecting
+// Retry-After) until the operation has finished and the terminal resource is returned.
+//
+// The status URL is taken from the Operation-Location, Azure-AsyncOperation or Location header.
+// If the status response has a JSON "status" field it is used to tell if the operation has
+// finished; once it has succeeded the resource is fetched from "resourceLocation" (or the Location
+// header). A status response without a "status" field is considered to be the terminal resource.
+// If the operation fails ErrOperationFailed is returned along with the status response. ro (which
+// may be nil) is used for every polling request
+func WaitForOperation(ctx context.Context, resp *Response, ro *RequestOptions) (*Response, error) {
+	if resp.Error != nil {
+		return resp, resp.Error
+	}
+
+	statusURL := operationStatusURL(resp)
+
+	if resp.StatusCode != http.StatusAccepted || statusURL == "" {
+		return resp, nil
+	}
+
+	resourceURL := resolveOperationURL(resp, resp.Header.Get("Location"))
+
+	pollOptions := &RequestOptions{}
+
+	if ro != nil {
+		roCopy := *ro
+		pollOptions = &roCopy
+	}
+
+	pollOptions.Context = ctx
+
+	for {
+		delay, ok := parseRetryAfter(resp.Header)
+
+		if !ok {
+			delay = defaultOperationPollInterval
+		}
+
+		discardResponse(resp)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, ctx.Err()
+		}
+
+		var err error
+
+		if resp, err = Get(statusURL, pollOptions); err != nil {
+			return resp, err
+		}
+
+		if resp.StatusCode == http.StatusAccepted {
+			if newStatusURL := operationStatusURL(resp); newStatusURL != "" {
+				statusURL = newStatusURL
+			}
+			continue
+		}
+
+		if !resp.Ok {
+			return resp, fmt.Errorf("%w: status code %d", ErrOperationFailed, resp.StatusCode)
+		}
+
+		status, resourceLocation := parseOperationStatus(resp.Bytes())
+
+		if status == "" {
+			return resp, nil
+		}
+
+		if _, failed := operationFailed[status]; failed {
+			return resp, fmt.Errorf("%w: %s", ErrOperationFailed, status)
+		}
+
+		if _, succeeded := operationSucceeded[status]; !succeeded {
+			continue
+		}
+
+		if resourceLocation != "" {
+			resourceURL = resolveOperationURL(resp, resourceLocation)
+		}
+
+		if resourceURL == "" || resourceURL == statusURL {
+			return resp, nil
+		}
+
+		return Get(resourceURL, pollOptions)
+	}
+}
+
+// operationStatusURL returns the (absolute) URL that the status of the operation can be found at
+func operationStatusURL(resp *Response) string {
+	for _, header := range []string{"Operation-Location", "Azure-AsyncOperation", "Location"} {
+		if value := resp.Header.Get(header); value != "" {
+			return resolveOperationURL(resp, value)
+		}
+	}
+
+	return ""
+}
+
+// resolveOperationURL resolves a (possibly relative) URL against the URL of the request
+func resolveOperationURL(resp *Response, location string) string {
+	if location == "" {
+		return ""
+	}
+
+	locationURL, err := url.Parse(location)
+
+	if err != nil || resp.RawResponse == nil || resp.RawResponse.Request == nil {
+		return location
+	}
+
+	return resp.RawResponse.Request.URL.ResolveReference(locationURL).String()
+}
+
+// parseOperationStatus returns the (lower case) "status" and the "resourceLocation" fields of the body
+func parseOperationStatus(body []byte) (status, resourceLocation string) {
+	operation := map[string]interface{}{}
+
+	if err := json.Unmarshal(body, &operation); err != nil {
+		return "", ""
+	}
+
+	for key, value := range operation {
+		switch v := value.(type) {
+		case string:
+			switch strings.ToLower(key) {
+			case "status":
+				status = strings.ToLower(v)
+			case "resourcelocation":
+				resourceLocation = v
+			}
+		}
+	}
+
+	return status, resourceLocation
+}
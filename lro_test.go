@@ -0,0 +1,116 @@
+package grequests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForOperation(t *testing.T) {
+	var polls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start":
+			w.Header().Set("Operation-Location", "/operations/1")
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusAccepted)
+		case "/operations/1":
+			w.Header().Set("Retry-After", "0")
+			if atomic.AddInt32(&polls, 1) < 3 {
+				w.Write([]byte(`{"status":"Running"}`))
+				return
+			}
+			w.Write([]byte(`{"status":"Succeeded","resourceLocation":"/resources/1"}`))
+		case "/resources/1":
+			w.Write([]byte(`{"id":1}`))
+		}
+	}))
+	defer ts.Close()
+
+	resp, err := Post(ts.URL+"/start", nil)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	resp, err = WaitForOperation(context.Background(), resp, nil)
+
+	if err != nil {
+		t.Fatal("Operation failed: ", err)
+	}
+
+	if resp.String() != `{"id":1}` {
+		t.Error("Terminal resource was not returned: ", resp.String())
+	}
+
+	if atomic.LoadInt32(&polls) != 3 {
+		t.Error("Status was not polled until the operation finished: ", polls)
+	}
+}
+
+func TestWaitForOperationLocationOnly(t *testing.T) {
+	var polls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" || atomic.AddInt32(&polls, 1) < 2 {
+			w.Header().Set("Location", "/resources/1")
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Write([]byte("resource"))
+	}))
+	defer ts.Close()
+
+	resp, _ := Put(ts.URL+"/start", nil)
+
+	resp, err := WaitForOperation(context.Background(), resp, nil)
+
+	if err != nil {
+		t.Fatal("Operation failed: ", err)
+	}
+
+	if resp.String() != "resource" {
+		t.Error("Terminal resource was not returned: ", resp.String())
+	}
+}
+
+func TestWaitForOperationFailed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			w.Header().Set("Azure-AsyncOperation", "/operations/1")
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Write([]byte(`{"status":"Failed"}`))
+	}))
+	defer ts.Close()
+
+	resp, _ := Delete(ts.URL+"/start", nil)
+
+	if _, err := WaitForOperation(context.Background(), resp, nil); !errors.Is(err, ErrOperationFailed) {
+		t.Error("Expected the operation to fail: ", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if delay, ok := parseRetryAfter(http.Header{"Retry-After": {"3"}}); !ok || delay != 3*time.Second {
+		t.Error("Invalid Retry-After seconds: ", delay)
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+
+	if delay, ok := parseRetryAfter(http.Header{"Retry-After": {date}}); !ok || delay < 59*time.Minute {
+		t.Error("Invalid Retry-After date: ", delay)
+	}
+
+	if _, ok := parseRetryAfter(http.Header{}); ok {
+		t.Error("Missing Retry-After was parsed")
+	}
+}
@@ -0,0 +1,344 @@
+// Package mock provides an http.RoundTripper that returns canned responses so code that uses
+// grequests can be tested without starting a server. Set a *Transport as the Transport of the
+// RequestOptions (or of the RequestOptions used to create a Session):
+//
+//	transport := mock.NewTransport()
+//	transport.On("GET", "https://api.example.com/users/*").RespondJSON(200, user)
+//
+//	resp, err := grequests.Get("https://api.example.com/users/1", &grequests.RequestOptions{Transport: transport})
+//
+//	transport.AssertCalled(t, "GET", "https://api.example.com/users/1")
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ErrNoRoute is the error returned when a request doesn't match any route
+var ErrNoRoute = errors.New("mock: No route matches the request")
+
+// Transport is an http.RoundTripper that returns the response of the first route that matches
+// the request. Every request is recorded so it can be asserted on. A Transport is safe for concurrent use
+type Transport struct {
+	mu     sync.Mutex
+	routes []*Route
+	calls  []Call
+}
+
+// Call is a request that has been sent through the Transport
+type Call struct {
+	// Method is the HTTP verb of the request
+	Method string
+
+	// URL is the complete URL of the request
+	URL string
+
+	// Header contains the headers of the request
+	Header http.Header
+
+	// Body is the body of the request
+	Body []byte
+}
+
+// Responder returns the response to a request
+type Responder func(*http.Request) (*http.Response, error)
+
+// Route returns canned responses to the requests that match its method and URL pattern
+type Route struct {
+	method  string
+	pattern *regexp.Regexp
+	source  string
+
+	mu         sync.Mutex
+	header     http.Header
+	responders []Responder
+	calls      int
+}
+
+// NewTransport returns a Transport without any routes
+func NewTransport() *Transport {
+	return &Transport{}
+}
+
+// On adds a route for the method ("" or "*" matches every method) and the URL pattern. Within the
+// pattern "*" matches any amount of characters. If the pattern doesn't contain a query string the
+// query string of the request is ignored. Routes are matched in the order they were added
+func (t *Transport) On(method, pattern string) *Route {
+	route := &Route{
+		method:  strings.ToUpper(method),
+		pattern: compilePattern(pattern),
+		source:  pattern,
+		header:  http.Header{},
+	}
+
+	t.mu.Lock()
+	t.routes = append(t.routes, route)
+	t.mu.Unlock()
+
+	return route
+}
+
+// compilePattern converts a URL pattern into a regular expression
+func compilePattern(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// matches reports if the route matches the request
+func (r *Route) matches(req *http.Request) bool {
+	if r.method != "" && r.method != "*" && r.method != req.Method {
+		return false
+	}
+
+	url := req.URL.String()
+
+	if !strings.Contains(r.source, "?") {
+		urlCopy := *req.URL
+		urlCopy.RawQuery = ""
+		urlCopy.ForceQuery = false
+		url = urlCopy.String()
+	}
+
+	return r.pattern.MatchString(url)
+}
+
+// Header adds a header to every response returned by the route
+func (r *Route) Header(key, value string) *Route {
+	r.mu.Lock()
+	r.header.Add(key, value)
+	r.mu.Unlock()
+
+	return r
+}
+
+// Respond adds a response with the status code and body. When a route has several responses
+// they are returned in order (the last one is repeated)
+func (r *Route) Respond(statusCode int, body string) *Route {
+	return r.RespondWith(func(req *http.Request) (*http.Response, error) {
+		return NewResponse(req, statusCode, body), nil
+	})
+}
+
+// RespondJSON adds a response with the status code and v encoded as JSON
+func (r *Route) RespondJSON(statusCode int, v interface{}) *Route {
+	body, err := json.Marshal(v)
+
+	if err != nil {
+		return r.RespondError(err)
+	}
+
+	return r.RespondWith(func(req *http.Request) (*http.Response, error) {
+		resp := NewResponse(req, statusCode, string(body))
+		resp.Header.Set("Content-Type", "application/json")
+		return resp, nil
+	})
+}
+
+// RespondError adds a response that fails with err (e.g. to simulate a network error)
+func (r *Route) RespondError(err error) *Route {
+	return r.RespondWith(func(*http.Request) (*http.Response, error) {
+		return nil, err
+	})
+}
+
+// RespondWith adds a response that is computed by the responder
+func (r *Route) RespondWith(responder Responder) *Route {
+	r.mu.Lock()
+	r.responders = append(r.responders, responder)
+	r.mu.Unlock()
+
+	return r
+}
+
+// CallCount returns the amount of requests the route has matched
+func (r *Route) CallCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.calls
+}
+
+// respond returns the next response of the route
+func (r *Route) respond(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+
+	r.calls++
+
+	if len(r.responders) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("mock: Route %s %s has no responses", r.method, r.source)
+	}
+
+	responder := r.responders[len(r.responders)-1]
+
+	if r.calls <= len(r.responders) {
+		responder = r.responders[r.calls-1]
+	}
+
+	header := r.header.Clone()
+
+	r.mu.Unlock()
+
+	resp, err := responder(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		for _, value := range values {
+			resp.Header.Add(key, value)
+		}
+	}
+
+	return resp, nil
+}
+
+// NewResponse returns a response to the request with the status code and body
+func NewResponse(req *http.Request, statusCode int, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// RoundTrip records the request and returns the response of the first route that matches it
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := Call{Method: req.Method, URL: req.URL.String(), Header: req.Header.Clone()}
+
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		call.Body = body
+
+		// The responder may read the body as well (without modifying the original request)
+		reqCopy := *req
+		reqCopy.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req = &reqCopy
+	}
+
+	t.mu.Lock()
+
+	t.calls = append(t.calls, call)
+
+	var route *Route
+
+	for _, candidate := range t.routes {
+		if candidate.matches(req) {
+			route = candidate
+			break
+		}
+	}
+
+	t.mu.Unlock()
+
+	if route == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoRoute, req.Method, req.URL)
+	}
+
+	return route.respond(req)
+}
+
+// Calls returns every request that has been sent through the transport (in order)
+func (t *Transport) Calls() []Call {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Call(nil), t.calls...)
+}
+
+// CallCount returns the amount of requests that match the method and URL pattern (see On)
+func (t *Transport) CallCount(method, pattern string) int {
+	matcher := &Route{method: strings.ToUpper(method), pattern: compilePattern(pattern), source: pattern}
+
+	count := 0
+
+	for _, call := range t.Calls() {
+		req, err := http.NewRequest(call.Method, call.URL, nil)
+
+		if err == nil && matcher.matches(req) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// AssertCalled fails the test if no request matches the method and URL pattern
+func (t *Transport) AssertCalled(tb testing.TB, method, pattern string) {
+	tb.Helper()
+
+	if t.CallCount(method, pattern) == 0 {
+		tb.Errorf("mock: Expected a request to %s %s, got: %s", method, pattern, t.describeCalls())
+	}
+}
+
+// AssertNotCalled fails the test if a request matches the method and URL pattern
+func (t *Transport) AssertNotCalled(tb testing.TB, method, pattern string) {
+	tb.Helper()
+
+	if n := t.CallCount(method, pattern); n != 0 {
+		tb.Errorf("mock: Expected no requests to %s %s, got %d", method, pattern, n)
+	}
+}
+
+// AssertExpectations fails the test if a route hasn't been called
+func (t *Transport) AssertExpectations(tb testing.TB) {
+	tb.Helper()
+
+	t.mu.Lock()
+	routes := append([]*Route(nil), t.routes...)
+	t.mu.Unlock()
+
+	for _, route := range routes {
+		if route.CallCount() == 0 {
+			tb.Errorf("mock: Route %s %s was never called", route.method, route.source)
+		}
+	}
+}
+
+// describeCalls lists the requests that have been sent
+func (t *Transport) describeCalls() string {
+	calls := t.Calls()
+
+	if len(calls) == 0 {
+		return "no requests"
+	}
+
+	descriptions := make([]string, len(calls))
+
+	for i, call := range calls {
+		descriptions[i] = call.Method + " " + call.URL
+	}
+
+	return strings.Join(descriptions, ", ")
+}
+
+// compile time check that Transport is an http.RoundTripper
+var _ http.RoundTripper = (*Transport)(nil)
@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTransportRoutes(t *testing.T) {
+	transport := NewTransport()
+
+	transport.On("GET", "https://api.example.com/users/*").
+		Header("X-Route", "users").
+		Respond(http.StatusServiceUnavailable, "busy").
+		RespondJSON(http.StatusOK, map[string]string{"name": "levi"})
+
+	transport.On("*", "https://api.example.com/search?q=go").Respond(http.StatusOK, "results")
+
+	client := &http.Client{Transport: transport}
+
+	expected := []struct {
+		method, url, body string
+		statusCode        int
+	}{
+		{"GET", "https://api.example.com/users/1", "busy", http.StatusServiceUnavailable},
+		{"GET", "https://api.example.com/users/1?fields=name", `{"name":"levi"}`, http.StatusOK},
+		{"GET", "https://api.example.com/users/2", `{"name":"levi"}`, http.StatusOK},
+		{"POST", "https://api.example.com/search?q=go", "results", http.StatusOK},
+	}
+
+	for _, e := range expected {
+		req, _ := http.NewRequest(e.method, e.url, strings.NewReader("payload"))
+
+		resp, err := client.Do(req)
+
+		if err != nil {
+			t.Fatal("Request failed: ", err)
+		}
+
+		body, _ := ioutil.ReadAll(resp.Body)
+
+		if resp.StatusCode != e.statusCode || string(body) != e.body {
+			t.Errorf("%s %s returned %d %q", e.method, e.url, resp.StatusCode, body)
+		}
+	}
+
+	if _, err := client.Get("https://api.example.com/search?q=rust"); !errors.Is(err, ErrNoRoute) {
+		t.Error("Expected ErrNoRoute, got: ", err)
+	}
+
+	calls := transport.Calls()
+
+	if len(calls) != 5 || string(calls[3].Body) != "payload" || calls[3].Method != "POST" {
+		t.Errorf("Unexpected calls: %+v", calls)
+	}
+
+	if n := transport.CallCount("GET", "https://api.example.com/users/*"); n != 3 {
+		t.Error("Unexpected call count: ", n)
+	}
+
+	transport.AssertCalled(t, "GET", "https://api.example.com/users/2")
+	transport.AssertNotCalled(t, "DELETE", "*")
+	transport.AssertExpectations(t)
+}
+
+func TestTransportAssertionsFail(t *testing.T) {
+	transport := NewTransport()
+	transport.On("GET", "https://example.com").RespondError(errors.New("connection reset"))
+
+	if _, err := (&http.Client{Transport: transport}).Get("https://example.com"); err == nil {
+		t.Error("RespondError didn't fail the request")
+	}
+
+	transport.On("POST", "https://example.com").Respond(http.StatusCreated, "")
+
+	fake := &failRecorder{TB: t}
+
+	transport.AssertCalled(fake, "DELETE", "https://example.com")
+	transport.AssertNotCalled(fake, "GET", "https://example.com")
+	transport.AssertExpectations(fake)
+
+	if fake.failures != 3 {
+		t.Error("Expected every assertion to fail, got: ", fake.failures)
+	}
+}
+
+// failRecorder counts the failures of assertions instead of failing the test
+type failRecorder struct {
+	testing.TB
+	failures int
+}
+
+func (f *failRecorder) Helper() {}
+
+func (f *failRecorder) Errorf(format string, args ...interface{}) {
+	f.failures++
+}
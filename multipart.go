@@ -0,0 +1,231 @@
+package grequests
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+const (
+	// TransferEncodingBase64 will base64 encode a multipart part
+	TransferEncodingBase64 = "base64"
+
+	// TransferEncodingQuotedPrintable will quoted-printable encode a multipart part
+	TransferEncodingQuotedPrintable = "quoted-printable"
+
+	// base64LineLength is the maximum line length of base64 encoded MIME bodies (RFC 2045)
+	base64LineLength = 76
+)
+
+// MultipartBody is used to build a non form multipart body (e.g. multipart/mixed or
+// multipart/related). This is required by batch endpoints and MTOM style attachments.
+type MultipartBody struct {
+	// Subtype is the multipart subtype e.g. "mixed" or "related". The default is "mixed"
+	Subtype string
+
+	// Params are any additional parameters that you want within the Content-Type header of
+	// the request e.g. "type" and "start" for multipart/related bodies. The boundary is added for you
+	Params map[string]string
+
+	// Parts are the individual parts of the body – they will be written in order
+	Parts []MultipartPart
+}
+
+// MultipartPart is a single part within a MultipartBody
+type MultipartPart struct {
+	// ContentType is the Content-Type header of the part
+	ContentType string
+
+	// ContentID is the Content-ID header of the part. The value will be wrapped in angle brackets
+	// if it isn't already
+	ContentID string
+
+	// Headers are any other headers that you want to add to the part
+	Headers map[string]string
+
+	// TransferEncoding is the Content-Transfer-Encoding of the part. Contents will be encoded
+	// on the fly. See TransferEncodingBase64 and TransferEncodingQuotedPrintable
+	TransferEncoding string
+
+	// Contents is the body of the part. If the reader is also an io.Closer it will be closed
+	// once it has been written. The body can only be sent again (when the request is retried
+	// or redirected) if the contents of every part are a file or an io.ReaderAt e.g. a
+	// strings.Reader or bytes.Reader
+	Contents io.Reader
+}
+
+func createMultipartBodyRequest(httpMethod, userURL string, ro *RequestOptions) (*http.Request, error) {
+	sections := make([]multipartSection, 0, len(ro.Multipart.Parts))
+
+	for i, part := range ro.Multipart.Parts {
+		if part.Contents == nil {
+			closeSections(sections)
+			closeMultipartParts(ro.Multipart.Parts[i:])
+			return nil, errors.New("grequests: MultipartPart Contents cannot be nil")
+		}
+
+		if err := checkTransferEncoding(part.TransferEncoding); err != nil {
+			closeSections(sections)
+			closeMultipartParts(ro.Multipart.Parts[i:])
+			return nil, err
+		}
+
+		sections = append(sections, multipartSection{
+			header:           part.mimeHeader(),
+			contents:         part.Contents,
+			transferEncoding: part.TransferEncoding,
+			size:             readerSize(part.Contents),
+			reopen:           bodyReopener(part.Contents),
+		})
+	}
+
+	return newMultipartRequest(httpMethod, userURL, sections, ro.Multipart.contentType)
+}
+
+// closeMultipartParts closes the contents (that can be closed) of parts that won't be sent
+func closeMultipartParts(parts []MultipartPart) {
+	for _, part := range parts {
+		if closer, ok := part.Contents.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
+
+// contentType returns the Content-Type header of the multipart body
+func (m MultipartBody) contentType(boundary string) string {
+	subtype := m.Subtype
+
+	if subtype == "" {
+		subtype = "mixed"
+	}
+
+	params := make(map[string]string, len(m.Params)+1)
+
+	for key, value := range m.Params {
+		params[key] = value
+	}
+
+	params["boundary"] = boundary
+
+	return mime.FormatMediaType("multipart/"+subtype, params)
+}
+
+// mimeHeader returns the headers of the part
+func (p MultipartPart) mimeHeader() textproto.MIMEHeader {
+	h := make(textproto.MIMEHeader)
+
+	for key, value := range p.Headers {
+		h.Set(key, value)
+	}
+
+	if p.ContentType != "" {
+		h.Set("Content-Type", p.ContentType)
+	}
+
+	if p.ContentID != "" {
+		h.Set("Content-ID", formatContentID(p.ContentID))
+	}
+
+	if p.TransferEncoding != "" {
+		h.Set("Content-Transfer-Encoding", p.TransferEncoding)
+	}
+
+	return h
+}
+
+func formatContentID(contentID string) string {
+	if strings.HasPrefix(contentID, "<") && strings.HasSuffix(contentID, ">") {
+		return contentID
+	}
+
+	return "<" + contentID + ">"
+}
+
+// isIdentityTransferEncoding reports if the Content-Transfer-Encoding leaves the contents untouched
+func isIdentityTransferEncoding(transferEncoding string) bool {
+	switch strings.ToLower(transferEncoding) {
+	case "", "7bit", "8bit", "binary":
+		return true
+	}
+
+	return false
+}
+
+// checkTransferEncoding returns an error if we are unable to apply the Content-Transfer-Encoding
+func checkTransferEncoding(transferEncoding string) error {
+	switch strings.ToLower(transferEncoding) {
+	case "", "7bit", "8bit", "binary", TransferEncodingBase64, TransferEncodingQuotedPrintable:
+		return nil
+	}
+
+	return fmt.Errorf("grequests: Unsupported Content-Transfer-Encoding %q", transferEncoding)
+}
+
+// copyPart copies the contents into the part while applying the Content-Transfer-Encoding
+func copyPart(writer io.Writer, contents io.Reader, transferEncoding string) error {
+	var encoder io.WriteCloser
+
+	switch strings.ToLower(transferEncoding) {
+	case "", "7bit", "8bit", "binary":
+		if _, err := io.Copy(writer, contents); err != nil && err != io.EOF {
+			return err
+		}
+		return nil
+	case TransferEncodingBase64:
+		encoder = base64.NewEncoder(base64.StdEncoding, &lineWrapper{w: writer, lineLength: base64LineLength})
+	case TransferEncodingQuotedPrintable:
+		encoder = quotedprintable.NewWriter(writer)
+	default:
+		return fmt.Errorf("grequests: Unsupported Content-Transfer-Encoding %q", transferEncoding)
+	}
+
+	if _, err := io.Copy(encoder, contents); err != nil && err != io.EOF {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// lineWrapper inserts a CRLF after every lineLength bytes written
+type lineWrapper struct {
+	w          io.Writer
+	lineLength int
+	written    int
+}
+
+func (l *lineWrapper) Write(p []byte) (int, error) {
+	total := 0
+
+	for len(p) > 0 {
+		if l.written == l.lineLength {
+			if _, err := l.w.Write([]byte("\r\n")); err != nil {
+				return total, err
+			}
+			l.written = 0
+		}
+
+		chunk := p
+
+		if remaining := l.lineLength - l.written; len(chunk) > remaining {
+			chunk = chunk[:remaining]
+		}
+
+		n, err := l.w.Write(chunk)
+		total += n
+		l.written += n
+
+		if err != nil {
+			return total, err
+		}
+
+		p = p[n:]
+	}
+
+	return total, nil
+}
@@ -0,0 +1,200 @@
+package grequests
+
+import (
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"sync"
+)
+
+// multipartSection is a single part of a multipart body that is written while the body is sent
+type multipartSection struct {
+	header           textproto.MIMEHeader
+	transferEncoding string
+
+	// contents are closed once they have been written (if they are an io.Closer)
+	contents io.Reader
+
+	// size is the size of the contents (before they are encoded) or -1 if it isn't known
+	size int64
+
+	// reopen returns a new reader of the contents so the body can be sent again (it is nil if
+	// the contents can only be read once)
+	reopen func() (io.ReadCloser, error)
+}
+
+// newMultipartRequest returns a request whose multipart body is streamed from the sections as the request is
+// sent (so the contents are never buffered in memory). The Content-Length of the request is set when the size
+// of every section is known, otherwise the body is sent using chunked encoding. The request can only be retried
+// (or mirrored) if the contents of every section can be reopened
+func newMultipartRequest(httpMethod, userURL string, sections []multipartSection,
+	contentType func(boundary string) string) (*http.Request, error) {
+
+	body := &multipartStream{
+		sections: sections,
+		boundary: multipart.NewWriter(ioutil.Discard).Boundary(),
+	}
+
+	req, err := http.NewRequest(httpMethod, userURL, body)
+
+	if err != nil {
+		closeSections(sections)
+		return nil, err
+	}
+
+	req.ContentLength = multipartLength(sections, body.boundary)
+	req.GetBody = multipartGetBody(sections, body.boundary)
+	req.Header.Set("Content-Type", contentType(body.boundary))
+
+	return req, nil
+}
+
+// multipartLength returns the size of the multipart body or -1 if it can't be computed
+func multipartLength(sections []multipartSection, boundary string) int64 {
+	counter := &countingWriter{}
+
+	multipartWriter := multipart.NewWriter(counter)
+
+	if err := multipartWriter.SetBoundary(boundary); err != nil {
+		return -1
+	}
+
+	var contentsSize int64
+
+	for _, section := range sections {
+		if section.size < 0 || !isIdentityTransferEncoding(section.transferEncoding) {
+			return -1
+		}
+
+		if _, err := multipartWriter.CreatePart(section.header); err != nil {
+			return -1
+		}
+
+		contentsSize += section.size
+	}
+
+	if err := multipartWriter.Close(); err != nil {
+		return -1
+	}
+
+	return counter.n + contentsSize
+}
+
+// readerSize returns the amount of bytes left within the reader or -1 if it isn't known
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case *os.File:
+		info, err := v.Stat()
+
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+
+		offset, err := v.Seek(0, io.SeekCurrent)
+
+		if err != nil {
+			return -1
+		}
+
+		return info.Size() - offset
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case io.Seeker:
+		offset, err := v.Seek(0, io.SeekCurrent)
+
+		if err != nil {
+			return -1
+		}
+
+		end, err := v.Seek(0, io.SeekEnd)
+
+		if err != nil {
+			return -1
+		}
+
+		if _, err := v.Seek(offset, io.SeekStart); err != nil {
+			return -1
+		}
+
+		return end - offset
+	}
+
+	return -1
+}
+
+// closeSections closes the contents of all of the sections
+func closeSections(sections []multipartSection) {
+	for _, section := range sections {
+		if closer, ok := section.contents.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
+
+// multipartStream is the body of a multipart request. The body is written into a pipe
+// (by a separate goroutine) once the transport starts to read it
+type multipartStream struct {
+	sections []multipartSection
+	boundary string
+
+	once       sync.Once
+	pipeReader *io.PipeReader
+}
+
+func (m *multipartStream) start() {
+	pipeReader, pipeWriter := io.Pipe()
+	m.pipeReader = pipeReader
+
+	go func() {
+		pipeWriter.CloseWithError(m.write(pipeWriter))
+	}()
+}
+
+// write writes the multipart body and closes the contents of every section
+func (m *multipartStream) write(w io.Writer) error {
+	defer closeSections(m.sections)
+
+	multipartWriter := multipart.NewWriter(w)
+
+	if err := multipartWriter.SetBoundary(m.boundary); err != nil {
+		return err
+	}
+
+	for _, section := range m.sections {
+		writer, err := multipartWriter.CreatePart(section.header)
+
+		if err != nil {
+			return err
+		}
+
+		if err := copyPart(writer, section.contents, section.transferEncoding); err != nil {
+			return err
+		}
+	}
+
+	return multipartWriter.Close()
+}
+
+func (m *multipartStream) Read(p []byte) (int, error) {
+	m.once.Do(m.start)
+	return m.pipeReader.Read(p)
+}
+
+// Close stops the body from being written (which closes the contents of the sections)
+func (m *multipartStream) Close() error {
+	m.once.Do(m.start)
+	return m.pipeReader.Close()
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
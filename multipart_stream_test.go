@@ -0,0 +1,140 @@
+package grequests
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// closeRecorder closes the closed channel when the reader is closed
+type closeRecorder struct {
+	io.Reader
+	closed chan struct{}
+}
+
+func newCloseRecorder(contents io.Reader) *closeRecorder {
+	return &closeRecorder{Reader: contents, closed: make(chan struct{})}
+}
+
+func (c *closeRecorder) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func (c *closeRecorder) waitForClose(t *testing.T) {
+	select {
+	case <-c.closed:
+	case <-time.After(5 * time.Second):
+		t.Error("Contents were not closed")
+	}
+}
+
+func TestMultipartStreamSetsContentLength(t *testing.T) {
+	var contentLength int64
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLength = r.ContentLength
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error("Unable to parse multipart body: ", err)
+			return
+		}
+
+		f, _, err := r.FormFile("file")
+
+		if err != nil {
+			t.Error("File is missing: ", err)
+			return
+		}
+
+		b, _ := ioutil.ReadAll(f)
+		w.Write(b)
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "upload.txt")
+
+	if err := ioutil.WriteFile(path, []byte(strings.Repeat("grequests", 1000)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	fd, err := FileUploadFromDisk(path)
+
+	if err != nil {
+		t.Fatal("Unable to open file: ", err)
+	}
+
+	resp, err := Post(ts.URL, &RequestOptions{Files: fd, Data: map[string]string{"one": "two"}})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != strings.Repeat("grequests", 1000) {
+		t.Error("File contents were not streamed")
+	}
+
+	if contentLength <= 9000 {
+		t.Error("Content-Length was not set: ", contentLength)
+	}
+
+	if _, err := fd[0].FileContents.(*os.File).Stat(); err == nil {
+		t.Error("File was not closed")
+	}
+}
+
+func TestMultipartStreamUnknownLength(t *testing.T) {
+	var contentLength int64
+	var transferEncoding []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLength = r.ContentLength
+		transferEncoding = r.TransferEncoding
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error("Unable to parse multipart body: ", err)
+		}
+	}))
+	defer ts.Close()
+
+	contents := newCloseRecorder(io.MultiReader(strings.NewReader("one"), strings.NewReader("two")))
+
+	_, err := Post(ts.URL, &RequestOptions{Files: []FileUpload{{FileName: "a.txt", FileContents: contents}}})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if contentLength != -1 || len(transferEncoding) != 1 || transferEncoding[0] != "chunked" {
+		t.Errorf("Expected a chunked body, got %d %v", contentLength, transferEncoding)
+	}
+
+	contents.waitForClose(t)
+}
+
+func TestMultipartStreamClosedBeforeSending(t *testing.T) {
+	contents := newCloseRecorder(strings.NewReader("contents"))
+
+	req, err := buildHTTPRequest("POST", "http://httpbin.org/post", &RequestOptions{
+		Files: []FileUpload{{FileName: "a.txt", FileContents: contents}},
+	})
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	req.Body.Close()
+
+	if _, err := req.Body.Read(make([]byte, 1)); err == nil {
+		t.Error("Closed body could still be read")
+	}
+
+	// The writer closes the contents once it notices that the pipe has been closed
+	contents.waitForClose(t)
+}
@@ -0,0 +1,186 @@
+package grequests
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+func TestMultipartRelatedRequest(t *testing.T) {
+	ro := &RequestOptions{
+		Multipart: &MultipartBody{
+			Subtype: "related",
+			Params:  map[string]string{"type": "application/xop+xml", "start": "<root>"},
+			Parts: []MultipartPart{
+				{ContentType: "application/xop+xml", ContentID: "root", Contents: strings.NewReader("<Envelope/>")},
+				{ContentType: "image/png", ContentID: "<image>", Contents: strings.NewReader("png")},
+			},
+		},
+	}
+
+	req, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro)
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	if err != nil {
+		t.Fatal("Invalid Content-Type: ", err)
+	}
+
+	if mediaType != "multipart/related" || params["type"] != "application/xop+xml" || params["start"] != "<root>" {
+		t.Error("Content-Type was not properly built: ", req.Header.Get("Content-Type"))
+	}
+
+	mr := multipart.NewReader(req.Body, params["boundary"])
+
+	expected := []struct{ contentType, contentID, body string }{
+		{"application/xop+xml", "<root>", "<Envelope/>"},
+		{"image/png", "<image>", "png"},
+	}
+
+	for _, e := range expected {
+		part, err := mr.NextPart()
+
+		if err != nil {
+			t.Fatal("Unable to read part: ", err)
+		}
+
+		if part.Header.Get("Content-Type") != e.contentType {
+			t.Error("Invalid part Content-Type: ", part.Header.Get("Content-Type"))
+		}
+
+		if part.Header.Get("Content-ID") != e.contentID {
+			t.Error("Invalid part Content-ID: ", part.Header.Get("Content-ID"))
+		}
+
+		if b, _ := ioutil.ReadAll(part); string(b) != e.body {
+			t.Error("Invalid part body: ", string(b))
+		}
+	}
+}
+
+func TestMultipartMixedIsDefault(t *testing.T) {
+	ro := &RequestOptions{
+		Multipart: &MultipartBody{
+			Parts: []MultipartPart{{ContentType: "application/http", Contents: strings.NewReader("GET /a HTTP/1.1\r\n\r\n")}},
+		},
+	}
+
+	req, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro)
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/mixed; boundary=") {
+		t.Error("multipart/mixed is not the default: ", req.Header.Get("Content-Type"))
+	}
+}
+
+func TestMultipartNilContents(t *testing.T) {
+	ro := &RequestOptions{
+		Multipart: &MultipartBody{Parts: []MultipartPart{{ContentType: "text/plain"}}},
+	}
+
+	if _, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro); err == nil {
+		t.Error("A nil part was accepted")
+	}
+}
+
+func TestMultipartPartTransferEncoding(t *testing.T) {
+	contents := strings.Repeat("grequests ", 20)
+
+	ro := &RequestOptions{
+		Multipart: &MultipartBody{
+			Parts: []MultipartPart{
+				{ContentType: "text/plain", TransferEncoding: TransferEncodingBase64, Contents: strings.NewReader(contents)},
+				{ContentType: "text/plain", TransferEncoding: TransferEncodingQuotedPrintable, Contents: strings.NewReader("café")},
+			},
+		},
+	}
+
+	req, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro)
+
+	if err != nil {
+		t.Fatal("Unable to build request: ", err)
+	}
+
+	_, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	mr := multipart.NewReader(req.Body, params["boundary"])
+
+	part, err := mr.NextPart()
+
+	if err != nil {
+		t.Fatal("Unable to read part: ", err)
+	}
+
+	raw, _ := ioutil.ReadAll(part)
+
+	for _, line := range strings.Split(string(raw), "\r\n") {
+		if len(line) > base64LineLength {
+			t.Error("base64 line is too long: ", len(line))
+		}
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.Replace(string(raw), "\r\n", "", -1))
+
+	if err != nil || string(decoded) != contents {
+		t.Error("Part was not base64 encoded: ", err, string(raw))
+	}
+
+	// The multipart reader transparently decodes quoted-printable parts
+	part, err = mr.NextPart()
+
+	if err != nil {
+		t.Fatal("Unable to read part: ", err)
+	}
+
+	if b, _ := ioutil.ReadAll(part); string(b) != "café" {
+		t.Error("Part was not quoted-printable encoded: ", string(b))
+	}
+}
+
+func TestMultipartUnsupportedTransferEncoding(t *testing.T) {
+	ro := &RequestOptions{
+		Multipart: &MultipartBody{
+			Parts: []MultipartPart{{TransferEncoding: "uuencode", Contents: strings.NewReader("data")}},
+		},
+	}
+
+	if _, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro); err == nil {
+		t.Error("An unsupported transfer encoding was accepted")
+	}
+}
+
+func TestMultipartErrorClosesParts(t *testing.T) {
+	parts := []*closeRecorder{
+		newCloseRecorder(strings.NewReader("first")),
+		newCloseRecorder(strings.NewReader("second")),
+		newCloseRecorder(strings.NewReader("third")),
+	}
+
+	ro := &RequestOptions{
+		Multipart: &MultipartBody{
+			Parts: []MultipartPart{
+				{Contents: parts[0]},
+				{Contents: parts[1], TransferEncoding: "uuencode"},
+				{Contents: parts[2]},
+			},
+		},
+	}
+
+	if _, err := buildHTTPRequest("POST", "http://httpbin.org/post", ro); err == nil {
+		t.Fatal("An unsupported transfer encoding was accepted")
+	}
+
+	for _, part := range parts {
+		part.waitForClose(t)
+	}
+}
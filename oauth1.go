@@ -0,0 +1,245 @@
+package grequests
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The signature methods supported by OAuth1
+const (
+	OAuth1HMACSHA1 = "HMAC-SHA1"
+	OAuth1RSASHA1  = "RSA-SHA1"
+)
+
+// ErrOAuth1UnreadableBody is the error returned when the form body of a request that is signed
+// with OAuth1 can only be read once
+var ErrOAuth1UnreadableBody = errors.New("grequests: OAuth1 can't sign a form body that can only be read once")
+
+// OAuth1 is an Authorizer that signs requests with OAuth 1.0a (RFC 5849). The query string and
+// (form encoded) body of the request are included within the signature:
+//
+//	resp, err := grequests.Post("https://api.twitter.com/1.1/statuses/update.json", &grequests.RequestOptions{
+//		Data:       map[string]string{"status": "Hello"},
+//		Authorizer: grequests.OAuth1{ConsumerKey: key, ConsumerSecret: secret, Token: token, TokenSecret: tokenSecret},
+//	})
+type OAuth1 struct {
+	ConsumerKey    string
+	ConsumerSecret string
+
+	// Token and TokenSecret are the credentials of the user (they are empty when requesting a
+	// temporary token)
+	Token       string
+	TokenSecret string
+
+	// SignatureMethod is either OAuth1HMACSHA1 (the default) or OAuth1RSASHA1
+	SignatureMethod string
+
+	// PrivateKey is the key requests are signed with when the SignatureMethod is OAuth1RSASHA1
+	PrivateKey *rsa.PrivateKey
+}
+
+func (o OAuth1) signatureMethod() string {
+	if o.SignatureMethod == "" {
+		return OAuth1HMACSHA1
+	}
+
+	return o.SignatureMethod
+}
+
+// Apply signs the request
+func (o OAuth1) Apply(req *http.Request) error {
+	nonce := make([]byte, 16)
+
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	return o.sign(req, time.Now(), hex.EncodeToString(nonce))
+}
+
+// sign signs the request as if it was sent at the time with the nonce
+func (o OAuth1) sign(req *http.Request, now time.Time, nonce string) error {
+	oauthParams := map[string]string{
+		"oauth_consumer_key":     o.ConsumerKey,
+		"oauth_nonce":            nonce,
+		"oauth_signature_method": o.signatureMethod(),
+		"oauth_timestamp":        strconv.FormatInt(now.Unix(), 10),
+		"oauth_version":          "1.0",
+	}
+
+	if o.Token != "" {
+		oauthParams["oauth_token"] = o.Token
+	}
+
+	params, err := oauth1RequestParams(req)
+
+	if err != nil {
+		return err
+	}
+
+	for key, value := range oauthParams {
+		params.Add(key, value)
+	}
+
+	baseString := strings.Join([]string{
+		percentEncode(strings.ToUpper(req.Method)),
+		percentEncode(oauth1BaseURI(req.URL)),
+		percentEncode(oauth1NormalizedParams(params)),
+	}, "&")
+
+	signature, err := o.signature(baseString)
+
+	if err != nil {
+		return err
+	}
+
+	oauthParams["oauth_signature"] = signature
+
+	header := make([]string, 0, len(oauthParams))
+
+	for _, key := range sortedKeys(oauthParams) {
+		header = append(header, percentEncode(key)+`="`+percentEncode(oauthParams[key])+`"`)
+	}
+
+	req.Header.Set("Authorization", "OAuth "+strings.Join(header, ", "))
+
+	return nil
+}
+
+// signature signs the signature base string with the signature method
+func (o OAuth1) signature(baseString string) (string, error) {
+	switch o.signatureMethod() {
+	case OAuth1HMACSHA1:
+		mac := hmac.New(sha1.New, []byte(percentEncode(o.ConsumerSecret)+"&"+percentEncode(o.TokenSecret)))
+		mac.Write([]byte(baseString))
+
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+	case OAuth1RSASHA1:
+		if o.PrivateKey == nil {
+			return "", errors.New("grequests: OAuth1 RSA-SHA1 requires a PrivateKey")
+		}
+
+		hash := sha1.Sum([]byte(baseString))
+
+		signature, err := rsa.SignPKCS1v15(rand.Reader, o.PrivateKey, crypto.SHA1, hash[:])
+
+		if err != nil {
+			return "", err
+		}
+
+		return base64.StdEncoding.EncodeToString(signature), nil
+	default:
+		return "", errors.New("grequests: Unsupported OAuth1 signature method " + o.SignatureMethod)
+	}
+}
+
+// oauth1RequestParams returns the parameters of the query string and the form encoded body of the request
+func oauth1RequestParams(req *http.Request) (url.Values, error) {
+	params, err := url.ParseQuery(req.URL.RawQuery)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	if mediaType != "application/x-www-form-urlencoded" || req.Body == nil || req.Body == http.NoBody {
+		return params, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, ErrOAuth1UnreadableBody
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer body.Close()
+
+	contents, err := ioutil.ReadAll(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	form, err := url.ParseQuery(string(contents))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range form {
+		params[key] = append(params[key], values...)
+	}
+
+	return params, nil
+}
+
+// oauth1BaseURI returns the URL without its query string (and the default port)
+func oauth1BaseURI(u *url.URL) string {
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+
+	if port := u.Port(); (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		host = strings.ToLower(u.Hostname())
+	}
+
+	path := u.EscapedPath()
+
+	if path == "" {
+		path = "/"
+	}
+
+	return scheme + "://" + host + path
+}
+
+// oauth1NormalizedParams encodes the parameters sorted by their encoded keys (and then values)
+func oauth1NormalizedParams(params url.Values) string {
+	encoded := map[string][]string{}
+
+	for key, values := range params {
+		encodedKey := percentEncode(key)
+
+		for _, value := range values {
+			encoded[encodedKey] = append(encoded[encodedKey], percentEncode(value))
+		}
+	}
+
+	keys := make([]string, 0, len(encoded))
+
+	for key := range encoded {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(params))
+
+	for _, key := range keys {
+		values := encoded[key]
+		sort.Strings(values)
+
+		for _, value := range values {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
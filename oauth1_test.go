@@ -0,0 +1,101 @@
+package grequests
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The example from the Twitter documentation on creating a signature
+func TestOAuth1HMACSHA1(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://api.twitter.com/1.1/statuses/update.json?include_entities=true",
+		strings.NewReader("status=Hello%20Ladies%20%2b%20Gentlemen%2c%20a%20signed%20OAuth%20request%21"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	signer := OAuth1{
+		ConsumerKey:    "xvz1evFS4wEEPTGEFPHBog",
+		ConsumerSecret: "kAcSOqF21Fu85e7zjz7ZN2U4ZRhfV3WpwPAoE3Z7kBw",
+		Token:          "370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb",
+		TokenSecret:    "LswwdoUaIvS8ltyTt5jkRh4J50vUPVVHtR2YPi5kE",
+	}
+
+	if err := signer.sign(req, time.Unix(1318622958, 0), "kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `OAuth oauth_consumer_key="xvz1evFS4wEEPTGEFPHBog", oauth_nonce="kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg", ` +
+		`oauth_signature="hCtSmYh%2BiHYCEqBWrE7C7hYmtUk%3D", oauth_signature_method="HMAC-SHA1", oauth_timestamp="1318622958", ` +
+		`oauth_token="370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb", oauth_version="1.0"`
+
+	if req.Header.Get("Authorization") != expected {
+		t.Error("Unexpected Authorization header: ", req.Header.Get("Authorization"))
+	}
+}
+
+func TestOAuth1RSASHA1(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL+"/photos?size=original", &RequestOptions{
+		Params:     map[string]string{"file": "vacation.jpg"},
+		Authorizer: OAuth1{ConsumerKey: "dpf43f3p2l4k3l03", SignatureMethod: OAuth1RSASHA1, PrivateKey: key},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := url.Values{}
+
+	for _, param := range strings.Split(strings.TrimPrefix(resp.String(), "OAuth "), ", ") {
+		parts := strings.SplitN(param, "=", 2)
+		value, _ := url.QueryUnescape(strings.Trim(parts[1], `"`))
+		params.Set(parts[0], value)
+	}
+
+	if params.Get("oauth_token") != "" || params.Get("oauth_signature_method") != "RSA-SHA1" {
+		t.Fatal("Unexpected Authorization header: ", resp.String())
+	}
+
+	signature, _ := base64.StdEncoding.DecodeString(params.Get("oauth_signature"))
+	params.Del("oauth_signature")
+	params.Set("file", "vacation.jpg")
+	params.Set("size", "original")
+
+	baseString := "GET&" + percentEncode(oauth1BaseURI(resp.RawResponse.Request.URL)) + "&" + percentEncode(oauth1NormalizedParams(params))
+	hash := sha1.Sum([]byte(baseString))
+
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA1, hash[:], signature); err != nil {
+		t.Error("Invalid signature: ", err)
+	}
+}
+
+func TestOAuth1NormalizedParams(t *testing.T) {
+	params := url.Values{"a1": {"x"}, "a": {"z", "y"}, "b": {"2 q"}}
+
+	if normalized := oauth1NormalizedParams(params); normalized != "a=y&a=z&a1=x&b=2%20q" {
+		t.Error("Unexpected normalized parameters: ", normalized)
+	}
+
+	u, _ := url.Parse("HTTPS://Example.com:443")
+
+	if uri := oauth1BaseURI(u); uri != "https://example.com/" {
+		t.Error("Unexpected base URI: ", uri)
+	}
+}
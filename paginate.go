@@ -0,0 +1,195 @@
+package grequests
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// NextPageFunc returns the URL of the page after the response (or "" when it is the last page).
+// Relative URLs are resolved against the URL of the response
+type NextPageFunc func(resp *Response) (string, error)
+
+// Paginator iterates over the pages of a paginated API (see Session.Paginate). Like bufio.Scanner
+// Next fetches the next page which is then available through Response:
+//
+//	pages := session.Paginate("https://api.github.com/user/repos", nil, nil)
+//
+//	for pages.Next() {
+//		var repos []Repository
+//		pages.Response().JSON(&repos)
+//	}
+//
+//	if err := pages.Err(); err != nil { ... }
+type Paginator struct {
+	// MaxPages (if set) is the maximum amount of pages that will be fetched
+	MaxPages int
+
+	session  *Session
+	ro       *RequestOptions
+	nextPage NextPageFunc
+	next     string
+	resp     *Response
+	err      error
+	pages    int
+}
+
+// Paginate returns a Paginator that starts at the URL and fetches the next page using nextPage.
+// If nextPage is nil the "next" link of the Link header (RFC 5988) is followed
+func (s *Session) Paginate(url string, ro *RequestOptions, nextPage NextPageFunc) *Paginator {
+	if nextPage == nil {
+		nextPage = LinkHeaderNextPage
+	}
+
+	return &Paginator{session: s, ro: ro, nextPage: nextPage, next: url}
+}
+
+// Next fetches the next page. It returns false when there are no more pages or an error occurred
+// (a response that isn't 2xx is an error but remains available through Response)
+func (p *Paginator) Next() bool {
+	if p.err != nil || p.next == "" || (p.MaxPages > 0 && p.pages >= p.MaxPages) {
+		return false
+	}
+
+	resp, err := p.session.Get(p.next, p.ro)
+
+	p.resp = resp
+	p.pages++
+
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	if p.err = resp.RaiseForStatus(); p.err != nil {
+		return false
+	}
+
+	next, err := p.nextPage(resp)
+
+	if err != nil {
+		p.err = err
+		p.next = ""
+		return true
+	}
+
+	p.next = ""
+
+	if next == "" {
+		return true
+	}
+
+	base := resp.RawResponse.Request.URL
+
+	nextURL, err := base.Parse(next)
+
+	if err != nil {
+		p.err = err
+		return true
+	}
+
+	// A page that links to itself would be fetched forever
+	if nextURL.String() != base.String() {
+		p.next = nextURL.String()
+	}
+
+	return true
+}
+
+// Response returns the page that was fetched by the last call to Next
+func (p *Paginator) Response() *Response {
+	return p.resp
+}
+
+// Err returns the error that stopped the pagination (if any)
+func (p *Paginator) Err() error {
+	return p.err
+}
+
+// LinkHeaderNextPage returns the URL of the "next" link within the Link header of the response
+func LinkHeaderNextPage(resp *Response) (string, error) {
+	return parseLinkHeader(resp.Header.Values("Link"))["next"], nil
+}
+
+// JSONNextPage returns a NextPageFunc that reads the URL of the next page from the JSON body of the
+// response at the path of object keys (e.g. JSONNextPage("links", "next")). A missing or null value
+// means there are no more pages. The body remains available through the methods of the response
+func JSONNextPage(path ...string) NextPageFunc {
+	return func(resp *Response) (string, error) {
+		body := resp.Bytes()
+
+		if resp.Error != nil {
+			return "", resp.Error
+		}
+
+		var value interface{}
+
+		if err := json.Unmarshal(body, &value); err != nil {
+			return "", err
+		}
+
+		for _, key := range path {
+			object, ok := value.(map[string]interface{})
+
+			if !ok {
+				return "", nil
+			}
+
+			value = object[key]
+		}
+
+		switch next := value.(type) {
+		case nil:
+			return "", nil
+		case string:
+			return next, nil
+		default:
+			return "", fmt.Errorf("grequests: Next page at %q is a %T not a string", strings.Join(path, "."), value)
+		}
+	}
+}
+
+// parseLinkHeader returns the URL of every relation within the Link headers
+func parseLinkHeader(headers []string) map[string]string {
+	links := map[string]string{}
+
+	for _, header := range headers {
+		for header != "" {
+			start := strings.IndexByte(header, '<')
+			end := strings.IndexByte(header, '>')
+
+			if start < 0 || end < start {
+				break
+			}
+
+			link := header[start+1 : end]
+			header = header[end+1:]
+
+			params := header
+
+			if next := strings.IndexByte(header, '<'); next >= 0 {
+				params = header[:next]
+				header = header[next:]
+			} else {
+				header = ""
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+
+				if !ok || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+
+				// rel may contain several relations e.g. rel="next last"
+				for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(value), `",`)) {
+					if _, exists := links[strings.ToLower(rel)]; !exists {
+						links[strings.ToLower(rel)] = link
+					}
+				}
+			}
+		}
+	}
+
+	return links
+}
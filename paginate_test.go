@@ -0,0 +1,134 @@
+package grequests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestParseLinkHeader(t *testing.T) {
+	links := parseLinkHeader([]string{
+		`<https://api.github.com/user/repos?page=3>; rel="next", <https://api.github.com/user/repos?page=50>; rel="last"`,
+		`</first>; title="a;b"; rel="first prev"`,
+	})
+
+	expected := map[string]string{
+		"next":  "https://api.github.com/user/repos?page=3",
+		"last":  "https://api.github.com/user/repos?page=50",
+		"first": "/first",
+		"prev":  "/first",
+	}
+
+	if len(links) != len(expected) {
+		t.Fatal("Unexpected links: ", links)
+	}
+
+	for rel, link := range expected {
+		if links[rel] != link {
+			t.Errorf("Expected %s to be %q, got %q", rel, link, links[rel])
+		}
+	}
+}
+
+func TestPaginateLinkHeader(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`</items?page=%d>; rel="next", </items?page=3>; rel="last"`, page+1))
+		}
+
+		fmt.Fprintf(w, `{"page": %d}`, page)
+	}))
+	defer ts.Close()
+
+	pages := NewSession(nil).Paginate(ts.URL+"/items?page=1", nil, nil)
+
+	var seen []int
+
+	for pages.Next() {
+		var body struct{ Page int }
+
+		if err := pages.Response().JSON(&body); err != nil {
+			t.Fatal("Unable to decode page: ", err)
+		}
+
+		seen = append(seen, body.Page)
+	}
+
+	if pages.Err() != nil {
+		t.Fatal("Pagination failed: ", pages.Err())
+	}
+
+	if fmt.Sprint(seen) != "[1 2 3]" {
+		t.Error("Unexpected pages: ", seen)
+	}
+}
+
+func TestPaginateJSONNextPage(t *testing.T) {
+	var requests int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(`{"items": [1, 2], "links": {"next": "?cursor=abc"}}`))
+		case "abc":
+			w.Write([]byte(`{"items": [3], "links": {"next": null}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	pages := NewSession(nil).Paginate(ts.URL, nil, JSONNextPage("links", "next"))
+
+	var items []int
+
+	for pages.Next() {
+		var body struct{ Items []int }
+
+		if err := pages.Response().JSON(&body); err != nil {
+			t.Fatal("Unable to decode page: ", err)
+		}
+
+		items = append(items, body.Items...)
+	}
+
+	if pages.Err() != nil || fmt.Sprint(items) != "[1 2 3]" || requests != 2 {
+		t.Error("Unexpected pagination: ", items, requests, pages.Err())
+	}
+
+	pages = NewSession(nil).Paginate(ts.URL+"?cursor=bad", nil, JSONNextPage("next"))
+
+	if pages.Next() {
+		t.Error("A 400 page was returned")
+	}
+
+	if _, ok := pages.Err().(*Non2xxError); !ok || pages.Response().StatusCode != http.StatusBadRequest {
+		t.Error("Expected a *Non2xxError, got: ", pages.Err())
+	}
+}
+
+func TestPaginateMaxPages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<?page=`+strconv.Itoa(len(r.URL.RawQuery))+`>; rel="next"`)
+	}))
+	defer ts.Close()
+
+	pages := NewSession(nil).Paginate(ts.URL, nil, nil)
+	pages.MaxPages = 2
+
+	count := 0
+
+	for pages.Next() {
+		count++
+	}
+
+	if count != 2 || pages.Err() != nil {
+		t.Error("MaxPages wasn't respected: ", count, pages.Err())
+	}
+}
@@ -0,0 +1,84 @@
+package grequests
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Backoff returns the amount of time to wait before the next attempt. attempt is the
+// amount of attempts that have been made so far (starting at 1)
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff always waits for the same amount of time
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles the delay after every attempt (starting at initial) until it
+// reaches max. Up to 50% of random jitter is removed from every delay so that many clients
+// don't retry in lockstep
+func ExponentialBackoff(initial, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := initial
+
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+
+		if delay > max {
+			delay = max
+		}
+
+		if delay <= 0 {
+			return 0
+		}
+
+		return delay - time.Duration(rand.Int63n(int64(delay)/2+1))
+	}
+}
+
+// PollUntil repeatedly issues the request returned by request until condition reports that it is
+// done (or returns an error) or the context expires. The context passed to request should be set as
+// the `Context` of the RequestOptions. condition is called for every response – including responses
+// that failed (resp.Error is set) so it can decide if the failure is transient. The response that
+// satisfied the condition is returned. If the context expires the last response is returned along with
+// the error of the context
+func PollUntil(ctx context.Context, request func(ctx context.Context) (*Response, error),
+	condition func(*Response) (done bool, err error), backoff Backoff) (*Response, error) {
+
+	if backoff == nil {
+		backoff = ExponentialBackoff(time.Second, 30*time.Second)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := request(ctx)
+
+		if resp == nil {
+			resp = &Response{Error: err}
+		}
+
+		done, err := condition(resp)
+
+		if err != nil {
+			return resp, err
+		}
+
+		if done {
+			return resp, nil
+		}
+
+		discardResponse(resp)
+
+		timer := time.NewTimer(backoff(attempt))
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, ctx.Err()
+		}
+	}
+}
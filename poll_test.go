@@ -0,0 +1,81 @@
+package grequests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPollUntilConditionMet(t *testing.T) {
+	var hits int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			w.Write([]byte(`{"status":"pending"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"ready"}`))
+	}))
+	defer ts.Close()
+
+	resp, err := PollUntil(context.Background(),
+		func(ctx context.Context) (*Response, error) {
+			return Get(ts.URL, &RequestOptions{Context: ctx})
+		},
+		func(resp *Response) (bool, error) {
+			if resp.Error != nil {
+				return false, nil
+			}
+
+			status := struct{ Status string }{}
+
+			if err := resp.JSON(&status); err != nil {
+				return false, err
+			}
+
+			return status.Status == "ready", nil
+		},
+		ConstantBackoff(time.Millisecond))
+
+	if err != nil {
+		t.Fatal("Polling failed: ", err)
+	}
+
+	if !resp.Ok || atomic.LoadInt32(&hits) != 3 {
+		t.Error("Polling did not stop once the condition was met: ", hits)
+	}
+}
+
+func TestPollUntilContextExpires(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := PollUntil(ctx,
+		func(ctx context.Context) (*Response, error) {
+			return Get(ts.URL, &RequestOptions{Context: ctx})
+		},
+		func(resp *Response) (bool, error) { return false, nil },
+		ConstantBackoff(10*time.Millisecond))
+
+	if err != context.DeadlineExceeded {
+		t.Error("Expected the context to expire: ", err)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	for attempt, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 10: time.Second} {
+		delay := backoff(attempt)
+
+		if delay < max/2 || delay > max {
+			t.Error("Invalid delay: ", attempt, delay)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package grequests
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxParkedConnAge is how long a preconnected connection is kept before it is considered
+// stale (servers close connections that never send a request)
+const maxParkedConnAge = 30 * time.Second
+
+// parkedConn is a connection established by Preconnect that hasn't been used yet
+type parkedConn struct {
+	conn     net.Conn
+	parkedAt time.Time
+}
+
+// parkedConns holds the connections established by Preconnect until the transport
+// dials the same address
+type parkedConns struct {
+	mu    sync.Mutex
+	conns map[string][]parkedConn
+}
+
+// park stores the connection for the address
+func (p *parkedConns) park(key string, conn net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.conns[key] = append(p.conns[key], parkedConn{conn: conn, parkedAt: time.Now()})
+}
+
+// take returns a parked connection for the address (or nil), closing any that went stale
+func (p *parkedConns) take(key string) net.Conn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.conns[key]) > 0 {
+		parked := p.conns[key][0]
+		p.conns[key] = p.conns[key][1:]
+
+		if time.Since(parked.parkedAt) < maxParkedConnAge {
+			return parked.conn
+		}
+
+		parked.conn.Close()
+	}
+
+	return nil
+}
+
+// closeAll closes every parked connection
+func (p *parkedConns) closeAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, conns := range p.conns {
+		for _, parked := range conns {
+			parked.conn.Close()
+		}
+
+		delete(p.conns, key)
+	}
+}
+
+// dialFunc is the signature of http.Transport.DialContext
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// preconnector dials the connections for Preconnect and hands them to the transport when it dials
+// the same address. It is installed when the session builds its transport (before the transport
+// serves any request) as the transport fields can't be changed once it is in use. Only the TCP
+// dialer is wrapped (it dials as before when there isn't a parked connection) so the transport
+// still performs the TLS handshake (and HTTP/2 negotiation) itself
+type preconnector struct {
+	transport *http.Transport
+	parked    *parkedConns
+	dial      dialFunc
+}
+
+// unwrapHTTPTransport returns the *http.Transport of the round tripper (looking through the cache) or nil
+func unwrapHTTPTransport(roundTripper http.RoundTripper) *http.Transport {
+	if cache, ok := roundTripper.(*cacheTransport); ok {
+		roundTripper = cache.transport
+	}
+
+	transport, ok := roundTripper.(*http.Transport)
+
+	if !ok || transport == http.DefaultTransport {
+		return nil
+	}
+
+	return transport
+}
+
+// newPreconnector makes the transport use the parked connections before dialing new ones. It returns nil
+// if the round tripper isn't an *http.Transport or it has its own TLS dialer (which wouldn't use them)
+func newPreconnector(roundTripper http.RoundTripper) *preconnector {
+	transport := unwrapHTTPTransport(roundTripper)
+
+	if transport == nil || transport.DialTLSContext != nil || transport.DialTLS != nil {
+		return nil
+	}
+
+	p := &preconnector{
+		transport: transport,
+		parked:    &parkedConns{conns: map[string][]parkedConn{}},
+		dial:      transportDialer(transport),
+	}
+
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if conn := p.parked.take(addr); conn != nil {
+			return conn, nil
+		}
+
+		return p.dial(ctx, network, addr)
+	}
+
+	return p
+}
+
+// transportDialer returns the function the transport uses to open TCP connections
+func transportDialer(transport *http.Transport) dialFunc {
+	if transport.DialContext != nil {
+		return transport.DialContext
+	}
+
+	if transport.Dial != nil {
+		dial := transport.Dial
+
+		return func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dial(network, addr)
+		}
+	}
+
+	return (&net.Dialer{}).DialContext
+}
+
+// preconnectAddress returns the scheme and the host:port that a host (either a URL or a bare host name,
+// which is assumed to be HTTPS) will be dialed on
+func preconnectAddress(host string) (string, string, error) {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	parsedURL, err := url.Parse(host)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if parsedURL.Host == "" {
+		return "", "", ErrInvalidPreconnectHost
+	}
+
+	port := parsedURL.Port()
+
+	switch {
+	case port != "":
+	case parsedURL.Scheme == "http":
+		port = "80"
+	case parsedURL.Scheme == "https":
+		port = "443"
+	default:
+		return "", "", ErrInvalidPreconnectHost
+	}
+
+	return parsedURL.Scheme, net.JoinHostPort(parsedURL.Hostname(), port), nil
+}
+
+// Preconnect performs the DNS lookup and TCP handshake for each of the hosts ahead of time and parks
+// the connections so that the first request to each host doesn't pay for establishing the connection
+// (the TLS handshake of HTTPS hosts is still performed by the transport). Hosts are either URLs
+// (e.g. "http://example.com:8080") or bare host names, which are assumed to be HTTPS. A connection
+// that isn't used within 30 seconds is discarded. The session must use the *http.Transport that
+// NewSession creates for it (a Transport or HTTPClient supplied in the RequestOptions isn't supported)
+func (s *Session) Preconnect(ctx context.Context, hosts ...string) error {
+	p := s.preconnector
+
+	if p == nil || s.HTTPClient == nil || unwrapHTTPTransport(s.HTTPClient.Transport) != p.transport {
+		return ErrPreconnectUnsupported
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(hosts))
+
+	for i, host := range hosts {
+		_, addr, err := preconnectAddress(host)
+
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int, addr string) {
+			defer wg.Done()
+
+			conn, err := p.dial(ctx, "tcp", addr)
+
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			p.parked.park(addr, conn)
+		}(i, addr)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package grequests
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"sync/atomic"
+	"testing"
+)
+
+func testPreconnect(t *testing.T, ts *httptest.Server, useTLS bool) {
+	var newConns int32
+
+	ts.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&newConns, 1)
+		}
+	}
+
+	if useTLS {
+		ts.StartTLS()
+	} else {
+		ts.Start()
+	}
+	defer ts.Close()
+
+	session := NewSession(&RequestOptions{InsecureSkipVerify: true})
+
+	if err := session.Preconnect(context.Background(), ts.URL); err != nil {
+		t.Fatal("Unable to preconnect: ", err)
+	}
+
+	// The transport still performs the TLS handshake so only dialing is traced
+	var dialed bool
+
+	resp, err := session.Get(ts.URL, &RequestOptions{Trace: &httptrace.ClientTrace{
+		ConnectStart: func(network, addr string) { dialed = true },
+	}})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "ok" {
+		t.Error("Unexpected body: ", resp.String())
+	}
+
+	if dialed {
+		t.Error("Request did not use the preconnected connection")
+	}
+
+	if n := atomic.LoadInt32(&newConns); n != 1 {
+		t.Error("Expected a single connection, got: ", n)
+	}
+}
+
+func TestPreconnectHTTP(t *testing.T) {
+	testPreconnect(t, httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})), false)
+}
+
+func TestPreconnectHTTPS(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil {
+			t.Error("Request was not made over TLS")
+		}
+
+		w.Write([]byte("ok"))
+	}))
+
+	testPreconnect(t, ts, true)
+}
+
+func TestPreconnectUnsupported(t *testing.T) {
+	session := &Session{HTTPClient: http.DefaultClient}
+
+	if err := session.Preconnect(context.Background(), "example.com"); err != ErrPreconnectUnsupported {
+		t.Error("Expected ErrPreconnectUnsupported, got: ", err)
+	}
+}
+
+func TestPreconnectAddress(t *testing.T) {
+	tests := map[string]string{
+		"example.com":              "https://example.com:443",
+		"http://example.com":       "http://example.com:80",
+		"https://example.com:8443": "https://example.com:8443",
+	}
+
+	for host, expected := range tests {
+		scheme, addr, err := preconnectAddress(host)
+
+		if err != nil || scheme+"://"+addr != expected {
+			t.Errorf("%s: expected %s, got %s://%s (%v)", host, expected, scheme, addr, err)
+		}
+	}
+
+	if _, _, err := preconnectAddress("ftp://example.com"); err != ErrInvalidPreconnectHost {
+		t.Error("Expected ErrInvalidPreconnectHost, got: ", err)
+	}
+}
+
+func TestPreconnectHTTP2(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	session := NewSession(&RequestOptions{InsecureSkipVerify: true})
+
+	if err := session.Preconnect(context.Background(), ts.URL); err != nil {
+		t.Fatal("Unable to preconnect: ", err)
+	}
+
+	resp, err := session.Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "HTTP/2.0" {
+		t.Error("Preconnected connection did not negotiate HTTP/2: ", resp.String())
+	}
+}
+
+func TestPreconnectWhileServing(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	session := NewSession(nil)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 10; i++ {
+			if _, err := session.Get(ts.URL, nil); err != nil {
+				t.Error("Request failed: ", err)
+			}
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		if err := session.Preconnect(context.Background(), ts.URL); err != nil {
+			t.Error("Unable to preconnect: ", err)
+		}
+	}
+
+	<-done
+}
+
+func TestPreconnectUserTransport(t *testing.T) {
+	session := NewSession(&RequestOptions{Transport: &http.Transport{}})
+
+	if err := session.Preconnect(context.Background(), "example.com"); err != ErrPreconnectUnsupported {
+		t.Error("Expected ErrPreconnectUnsupported, got: ", err)
+	}
+}
+
+func TestPreconnectKeepsTransportTLS(t *testing.T) {
+	session := NewSession(nil)
+	transport := unwrapHTTPTransport(session.HTTPClient.Transport)
+
+	if transport == nil || transport.DialTLSContext != nil || transport.DialTLS != nil {
+		t.Error("Session transport does not perform the TLS handshake itself")
+	}
+}
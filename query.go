@@ -0,0 +1,251 @@
+package grequests
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// QueryValues encodes a struct (or a pointer to a struct) into query parameters. The name of each
+// parameter is taken from the `url` struct tag of the field (or the field name if there is no tag) and
+// the tag may contain the following options after the name:
+//
+//	omitempty - the field is left out when it has its zero value
+//	comma     - slices are joined with commas instead of repeating the parameter
+//	int       - booleans are encoded as 1 or 0
+//	unix      - times are encoded as unix timestamps (instead of RFC 3339)
+//
+// A field with the tag `url:"-"` is ignored. Slices and arrays repeat the parameter, nil pointers
+// are left out, embedded structs are flattened and nested structs are encoded as "parent[child]".
+// Values implementing encoding.TextMarshaler are encoded using MarshalText
+func QueryValues(v interface{}) (url.Values, error) {
+	values := url.Values{}
+
+	rv := reflect.ValueOf(v)
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return values, nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("grequests: QueryStruct must be a struct, got %T", v)
+	}
+
+	if err := encodeQueryStruct(values, rv, ""); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// queryTag is the parsed `url` struct tag of a field
+type queryTag struct {
+	name      string
+	omitEmpty bool
+	comma     bool
+	intBool   bool
+	unix      bool
+}
+
+func parseQueryTag(field reflect.StructField) queryTag {
+	parts := strings.Split(field.Tag.Get("url"), ",")
+
+	tag := queryTag{name: parts[0]}
+
+	for _, option := range parts[1:] {
+		switch option {
+		case "omitempty":
+			tag.omitEmpty = true
+		case "comma":
+			tag.comma = true
+		case "int":
+			tag.intBool = true
+		case "unix":
+			tag.unix = true
+		}
+	}
+
+	return tag
+}
+
+func encodeQueryStruct(values url.Values, rv reflect.Value, prefix string) error {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+
+		if field.PkgPath != "" && !field.Anonymous { // unexported
+			continue
+		}
+
+		tag := parseQueryTag(field)
+
+		if tag.name == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+
+		if tag.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		for fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				break
+			}
+
+			fv = fv.Elem()
+		}
+
+		if fv.Kind() == reflect.Ptr {
+			continue
+		}
+
+		// Embedded structs are flattened into the parent
+		if field.Anonymous && tag.name == "" && fv.Kind() == reflect.Struct && !isQueryScalar(fv) {
+			if err := encodeQueryStruct(values, fv, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := tag.name
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if prefix != "" {
+			name = prefix + "[" + name + "]"
+		}
+
+		if fv.Kind() == reflect.Struct && !isQueryScalar(fv) {
+			if err := encodeQueryStruct(values, fv, name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array) && !isQueryScalar(fv) {
+			encoded := make([]string, 0, fv.Len())
+
+			for j := 0; j < fv.Len(); j++ {
+				value, err := encodeQueryValue(fv.Index(j), tag)
+
+				if err != nil {
+					return fmt.Errorf("grequests: Unable to encode %s: %w", name, err)
+				}
+
+				encoded = append(encoded, value)
+			}
+
+			if tag.comma {
+				values.Add(name, strings.Join(encoded, ","))
+			} else {
+				values[name] = append(values[name], encoded...)
+			}
+			continue
+		}
+
+		value, err := encodeQueryValue(fv, tag)
+
+		if err != nil {
+			return fmt.Errorf("grequests: Unable to encode %s: %w", name, err)
+		}
+
+		values.Add(name, value)
+	}
+
+	return nil
+}
+
+// isQueryScalar reports if the value is encoded as a single value even though it may be a struct or slice
+func isQueryScalar(v reflect.Value) bool {
+	return v.Type() == timeType || v.Type().Implements(textMarshalerType) ||
+		(v.CanAddr() && v.Addr().Type().Implements(textMarshalerType))
+}
+
+// encodeQueryValue encodes a single value
+func encodeQueryValue(v reflect.Value, tag queryTag) (string, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType && v.CanInterface() {
+		t := v.Interface().(time.Time)
+
+		if tag.unix {
+			return strconv.FormatInt(t.Unix(), 10), nil
+		}
+
+		return t.Format(time.RFC3339), nil
+	}
+
+	if marshaler, ok := textMarshaler(v); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		if tag.intBool {
+			if v.Bool() {
+				return "1", nil
+			}
+			return "0", nil
+		}
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// textMarshaler returns the value as an encoding.TextMarshaler (if it is one)
+func textMarshaler(v reflect.Value) (encoding.TextMarshaler, bool) {
+	// Values reached through unexported embedded structs can't be used as interfaces
+	if !v.CanInterface() {
+		return nil, false
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		return v.Interface().(encoding.TextMarshaler), true
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textMarshalerType) {
+		return v.Addr().Interface().(encoding.TextMarshaler), true
+	}
+
+	return nil, false
+}
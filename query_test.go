@@ -0,0 +1,87 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type queryPage struct {
+	Page    int `url:"page,omitempty"`
+	PerPage int `url:"per_page,omitempty"`
+}
+
+type querySearch struct {
+	queryPage
+	Query    string    `url:"q"`
+	Tags     []string  `url:"tag"`
+	IDs      []int     `url:"ids,comma"`
+	Archived bool      `url:"archived,int"`
+	Since    time.Time `url:"since,omitempty"`
+	Until    time.Time `url:"until,unix"`
+	Owner    *string   `url:"owner"`
+	Filter   struct {
+		State string `url:"state"`
+	} `url:"filter"`
+	Ignored string `url:"-"`
+	hidden  string
+}
+
+func TestQueryValues(t *testing.T) {
+	until := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	search := querySearch{
+		queryPage: queryPage{Page: 2},
+		Query:     "grequests",
+		Tags:      []string{"go", "http"},
+		IDs:       []int{1, 2, 3},
+		Archived:  true,
+		Until:     until,
+		Ignored:   "ignored",
+		hidden:    "hidden",
+	}
+	search.Filter.State = "open"
+
+	values, err := QueryValues(&search)
+
+	if err != nil {
+		t.Fatal("Unable to encode struct: ", err)
+	}
+
+	expected := "archived=1&filter%5Bstate%5D=open&ids=1%2C2%2C3&page=2&q=grequests&tag=go&tag=http&until=1577934245"
+
+	if values.Encode() != expected {
+		t.Errorf("Invalid query:\n%s\n%s", values.Encode(), expected)
+	}
+}
+
+func TestQueryValuesNotStruct(t *testing.T) {
+	if _, err := QueryValues(map[string]string{}); err == nil {
+		t.Error("A map was encoded")
+	}
+
+	if _, err := QueryValues(struct{ C chan int }{}); err == nil {
+		t.Error("A channel was encoded")
+	}
+}
+
+func TestQueryStructIsSent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.RawQuery))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL+"?page=9", &RequestOptions{
+		QueryStruct: queryPage{Page: 1, PerPage: 50},
+		Params:      map[string]string{"per_page": "10"},
+	})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "page=1&per_page=10" {
+		t.Error("Query struct was not sent: ", resp.String())
+	}
+}
@@ -0,0 +1,251 @@
+package grequests
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// validatorSuffix is appended to the path of a partially downloaded file to store the ETag (or
+// Last-Modified date) of the file. It is sent within the If-Range header when the download is resumed
+const validatorSuffix = ".validator"
+
+// RangeHeader returns the value of a Range header requesting the bytes from start to end (inclusive).
+// A negative end requests everything from start until the end of the file
+func RangeHeader(start, end int64) string {
+	if end < 0 {
+		return "bytes=" + strconv.FormatInt(start, 10) + "-"
+	}
+
+	return "bytes=" + strconv.FormatInt(start, 10) + "-" + strconv.FormatInt(end, 10)
+}
+
+// ContentRange parses the Content-Range header of a 206 (Partial Content) response. start and end
+// are the (inclusive) positions of the returned bytes and size is the size of the entire file (-1 if
+// the server doesn't know it). ok is false if the header is missing or invalid
+func (r *Response) ContentRange() (start, end, size int64, ok bool) {
+	if r.Header == nil {
+		return 0, 0, 0, false
+	}
+
+	return parseContentRange(r.Header.Get("Content-Range"))
+}
+
+func parseContentRange(contentRange string) (start, end, size int64, ok bool) {
+	if !strings.HasPrefix(contentRange, "bytes ") {
+		return 0, 0, 0, false
+	}
+
+	positions := strings.SplitN(strings.TrimPrefix(contentRange, "bytes "), "/", 2)
+
+	if len(positions) != 2 {
+		return 0, 0, 0, false
+	}
+
+	bounds := strings.SplitN(positions[0], "-", 2)
+
+	if len(bounds) != 2 {
+		return 0, 0, 0, false
+	}
+
+	start, startErr := strconv.ParseInt(bounds[0], 10, 64)
+	end, endErr := strconv.ParseInt(bounds[1], 10, 64)
+
+	if startErr != nil || endErr != nil || start < 0 || end < start {
+		return 0, 0, 0, false
+	}
+
+	size = -1
+
+	if positions[1] != "*" {
+		var err error
+
+		if size, err = strconv.ParseInt(positions[1], 10, 64); err != nil || size <= end {
+			return 0, 0, 0, false
+		}
+	}
+
+	return start, end, size, true
+}
+
+// DownloadResumable downloads the URL into the file. If the file has already been partially downloaded
+// the download continues from the end of the file (using a Range request). The ETag (or Last-Modified date)
+// of the file is kept next to the partial file (with a ".validator" suffix) and sent within an If-Range
+// header, so if the file changed on the server it is downloaded again from the start. A partial file
+// without a validator (the server sent neither a strong ETag nor a Last-Modified date) is also downloaded
+// again from the start
+func DownloadResumable(url, path string, ro *RequestOptions) (DownloadResult, error) {
+	return downloadResumable(func(ro *RequestOptions) (*Response, error) {
+		return Get(url, ro)
+	}, DownloadItem{URL: url, Path: path, RequestOptions: ro})
+}
+
+// DownloadResumable works like DownloadResumable except that the session is used to request the file
+func (s *Session) DownloadResumable(url, path string, ro *RequestOptions) (DownloadResult, error) {
+	return downloadResumable(func(ro *RequestOptions) (*Response, error) {
+		return s.Get(url, ro)
+	}, DownloadItem{URL: url, Path: path, RequestOptions: ro})
+}
+
+func downloadResumable(get func(*RequestOptions) (*Response, error), item DownloadItem) (DownloadResult, error) {
+	result := DownloadResult{Item: item, Attempts: 1}
+
+	download, err := rangeDownload{url: item.URL, path: item.Path, resume: true}.run(get, item.RequestOptions)
+
+	result.BytesWritten = download.written
+	result.Resumed = download.resumed
+	result.Error = err
+
+	return result, err
+}
+
+// rangeDownload downloads a file into path (continuing a partial download if resume is set)
+type rangeDownload struct {
+	url    string
+	path   string
+	resume bool
+
+	// started (if set) is called once the response has been received with the offset the download
+	// continues from and the total size of the file (0 if it isn't known)
+	started func(offset, total int64)
+
+	// progress (if set) is called with the amount of bytes written to the file
+	progress func(n int64)
+}
+
+// rangeDownloadResult is the outcome of a rangeDownload
+type rangeDownloadResult struct {
+	offset  int64
+	total   int64
+	written int64
+	resumed bool
+
+	// started is true once started has been called
+	started bool
+}
+
+func (d rangeDownload) run(get func(*RequestOptions) (*Response, error), userOptions *RequestOptions) (rangeDownloadResult, error) {
+	var result rangeDownloadResult
+
+	ro := &RequestOptions{}
+
+	if userOptions != nil {
+		roCopy := *userOptions
+		ro = &roCopy
+	}
+
+	headers := make(map[string]string, len(ro.Headers)+2)
+
+	for key, value := range ro.Headers {
+		headers[key] = value
+	}
+
+	validatorPath := d.path + validatorSuffix
+
+	// A partial file without a validator is downloaded again from the start as the server can't tell us
+	// if the file changed (and we would append the bytes of a different file)
+	if d.resume {
+		validator, err := ioutil.ReadFile(validatorPath)
+
+		if s, statErr := os.Stat(d.path); statErr == nil && s.Size() > 0 && err == nil && len(validator) != 0 {
+			result.offset = s.Size()
+			headers["Range"] = RangeHeader(result.offset, -1)
+			headers["If-Range"] = string(validator)
+		}
+	}
+
+	ro.Headers = headers
+
+	resp, err := get(ro)
+
+	if err != nil {
+		return result, err
+	}
+
+	defer resp.Close()
+
+	// The file has already been completely downloaded
+	if result.offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		result.resumed = true
+		os.Remove(validatorPath)
+		return result, nil
+	}
+
+	if !resp.Ok {
+		return result, fmt.Errorf("grequests: Download of %s returned status code %d", d.url, resp.StatusCode)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+
+	// The server may ignore the range (or the file may have changed) and send us the entire file
+	if result.offset > 0 && resp.StatusCode == http.StatusPartialContent {
+		if start, _, _, ok := resp.ContentRange(); !ok || start != result.offset {
+			return result, fmt.Errorf("grequests: Download of %s returned an unexpected Content-Range %q",
+				d.url, resp.Header.Get("Content-Range"))
+		}
+
+		flags = os.O_WRONLY | os.O_APPEND
+		result.resumed = true
+	} else {
+		result.offset = 0
+
+		if d.resume {
+			saveValidator(validatorPath, resp.Header)
+		}
+	}
+
+	fd, err := os.OpenFile(d.path, flags, 0644)
+
+	if err != nil {
+		return result, err
+	}
+
+	defer fd.Close()
+
+	if resp.RawResponse.ContentLength >= 0 {
+		result.total = result.offset + resp.RawResponse.ContentLength
+	}
+
+	if d.started != nil {
+		d.started(result.offset, result.total)
+	}
+
+	result.started = true
+
+	written, err := io.Copy(fd, &progressReader{Reader: resp, progress: func(n int64) {
+		if d.progress != nil {
+			d.progress(n)
+		}
+	}})
+
+	result.written = written
+
+	if err != nil && err != io.EOF {
+		return result, err
+	}
+
+	os.Remove(validatorPath)
+
+	return result, nil
+}
+
+// saveValidator stores the validator of the file (a strong ETag or the Last-Modified date) so it
+// can be sent within If-Range when the download is resumed. Weak ETags can't be used with If-Range
+func saveValidator(validatorPath string, header http.Header) {
+	validator := header.Get("ETag")
+
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = header.Get("Last-Modified")
+	}
+
+	if validator == "" {
+		os.Remove(validatorPath)
+		return
+	}
+
+	ioutil.WriteFile(validatorPath, []byte(validator), 0644)
+}
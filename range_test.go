@@ -0,0 +1,137 @@
+package grequests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRangeServer serves the contents (which can be changed) with a strong ETag
+func newRangeServer(contents *string, requests *[]*http.Request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r)
+		w.Header().Set("ETag", `"`+*contents+`"`)
+		http.ServeContent(w, r, "file", time.Time{}, strings.NewReader(*contents))
+	}))
+}
+
+func TestRangeHeader(t *testing.T) {
+	if RangeHeader(10, -1) != "bytes=10-" || RangeHeader(0, 99) != "bytes=0-99" {
+		t.Error("Invalid range header")
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	if start, end, size, ok := parseContentRange("bytes 10-19/100"); !ok || start != 10 || end != 19 || size != 100 {
+		t.Error("Content-Range was not parsed: ", start, end, size, ok)
+	}
+
+	if _, _, size, ok := parseContentRange("bytes 10-19/*"); !ok || size != -1 {
+		t.Error("Unknown size was not parsed: ", size, ok)
+	}
+
+	for _, invalid := range []string{"", "bytes */100", "bytes 19-10/100", "bytes 0-100/100", "items 0-1/2"} {
+		if _, _, _, ok := parseContentRange(invalid); ok {
+			t.Error("Invalid Content-Range was parsed: ", invalid)
+		}
+	}
+}
+
+func TestDownloadResumableContinuesPartialFile(t *testing.T) {
+	contents := "0123456789abcdefghij"
+	var requests []*http.Request
+
+	ts := newRangeServer(&contents, &requests)
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "file")
+
+	// A download that was interrupted after 10 bytes
+	ioutil.WriteFile(path, []byte(contents[:10]), 0644)
+	ioutil.WriteFile(path+validatorSuffix, []byte(`"`+contents+`"`), 0644)
+
+	result, err := DownloadResumable(ts.URL, path, nil)
+
+	if err != nil {
+		t.Fatal("Download failed: ", err)
+	}
+
+	if !result.Resumed || result.BytesWritten != 10 {
+		t.Error("Download was not resumed: ", result)
+	}
+
+	if requests[0].Header.Get("Range") != "bytes=10-" || requests[0].Header.Get("If-Range") != `"`+contents+`"` {
+		t.Error("Range headers were not sent: ", requests[0].Header)
+	}
+
+	if b, _ := ioutil.ReadFile(path); string(b) != contents {
+		t.Error("File contents are invalid: ", string(b))
+	}
+
+	if _, err := os.Stat(path + validatorSuffix); !os.IsNotExist(err) {
+		t.Error("Validator was not removed")
+	}
+}
+
+func TestDownloadResumableRestartsChangedFile(t *testing.T) {
+	contents := "0123456789abcdefghij"
+	var requests []*http.Request
+
+	ts := newRangeServer(&contents, &requests)
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "file")
+
+	ioutil.WriteFile(path, []byte("old contents"), 0644)
+	ioutil.WriteFile(path+validatorSuffix, []byte(`"old"`), 0644)
+
+	result, err := DownloadResumable(ts.URL, path, nil)
+
+	if err != nil {
+		t.Fatal("Download failed: ", err)
+	}
+
+	if result.Resumed || result.BytesWritten != int64(len(contents)) {
+		t.Error("Changed file was resumed: ", result)
+	}
+
+	if b, _ := ioutil.ReadFile(path); string(b) != contents {
+		t.Error("File contents are invalid: ", string(b))
+	}
+}
+
+func TestDownloadResumableRestartsWithoutValidator(t *testing.T) {
+	contents := "0123456789abcdefghij"
+	var requests []*http.Request
+
+	ts := newRangeServer(&contents, &requests)
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "file")
+
+	// The validator of the partial file is unknown so it may be a different version of the file
+	ioutil.WriteFile(path, []byte("old contents"), 0644)
+
+	result, err := DownloadResumable(ts.URL, path, nil)
+
+	if err != nil {
+		t.Fatal("Download failed: ", err)
+	}
+
+	if result.Resumed || result.BytesWritten != int64(len(contents)) {
+		t.Error("Partial file without a validator was resumed: ", result)
+	}
+
+	if requests[0].Header.Get("Range") != "" {
+		t.Error("Range was requested without a validator: ", requests[0].Header)
+	}
+
+	if b, _ := ioutil.ReadFile(path); string(b) != contents {
+		t.Error("File contents are invalid: ", string(b))
+	}
+}
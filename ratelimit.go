@@ -0,0 +1,114 @@
+package grequests
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate that requests are sent at. Wait is called before every attempt
+// of a request (including retries) and blocks until the request may be sent. If Wait returns an
+// error (e.g. the context was cancelled) the request isn't sent and the error is returned
+type RateLimiter interface {
+	Wait(ctx context.Context, req *http.Request) error
+}
+
+// HostRateLimiter is a RateLimiter that allows up to Rate requests per second to each host, with
+// bursts of up to Burst requests. It is safe to share between requests and sessions
+type HostRateLimiter struct {
+	// Rate is the amount of requests per second that are allowed to each host
+	Rate float64
+
+	// Burst is the amount of requests that may be sent at once. The default is 1
+	Burst int
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// NewHostRateLimiter returns a HostRateLimiter that allows rate requests per second to each host
+func NewHostRateLimiter(rate float64, burst int) *HostRateLimiter {
+	return &HostRateLimiter{Rate: rate, Burst: burst}
+}
+
+// tokenBucket holds the tokens of a single host. tokens may be negative when requests
+// have reserved tokens that haven't been refilled yet
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Wait implements RateLimiter
+func (l *HostRateLimiter) Wait(ctx context.Context, req *http.Request) error {
+	if l.Rate <= 0 {
+		return nil
+	}
+
+	delay := l.reserve(req.URL.Host)
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel(req.URL.Host)
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token from the bucket of the host and returns how long to wait until the token is available
+func (l *HostRateLimiter) reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	burst := float64(l.Burst)
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	if l.buckets == nil {
+		l.buckets = map[string]*tokenBucket{}
+	}
+
+	now := time.Now()
+
+	bucket, ok := l.buckets[host]
+
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, last: now}
+		l.buckets[host] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * l.Rate
+	bucket.last = now
+
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+
+	bucket.tokens--
+
+	if bucket.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-bucket.tokens / l.Rate * float64(time.Second))
+}
+
+// cancel returns a reserved token to the bucket of the host
+func (l *HostRateLimiter) cancel(host string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if bucket, ok := l.buckets[host]; ok {
+		bucket.tokens++
+	}
+}
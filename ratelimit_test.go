@@ -0,0 +1,67 @@
+package grequests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHostRateLimiter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	session := NewSession(nil)
+	session.RateLimiter = NewHostRateLimiter(20, 2)
+
+	start := time.Now()
+
+	for i := 0; i < 6; i++ {
+		if _, err := session.Get(ts.URL, nil); err != nil {
+			t.Fatal("Request failed: ", err)
+		}
+	}
+
+	// 2 requests are allowed at once and the remaining 4 are spaced 50ms apart
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Error("Requests were not rate limited: ", elapsed)
+	}
+}
+
+func TestHostRateLimiterIsPerHost(t *testing.T) {
+	limiter := NewHostRateLimiter(1, 1)
+
+	first, _ := http.NewRequest("GET", "http://one.example.com/", nil)
+	second, _ := http.NewRequest("GET", "http://two.example.com/", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx, first); err != nil {
+		t.Error("First request was limited: ", err)
+	}
+
+	if err := limiter.Wait(ctx, second); err != nil {
+		t.Error("Request to another host was limited: ", err)
+	}
+
+	if err := limiter.Wait(ctx, first); err != context.DeadlineExceeded {
+		t.Error("Expected the context to expire, got: ", err)
+	}
+}
+
+func TestRateLimiterError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	limiter := NewHostRateLimiter(1, 1)
+
+	// Use up the token of the host
+	req, _ := http.NewRequest("GET", "http://127.0.0.1:1/", nil)
+	limiter.Wait(context.Background(), req)
+
+	if _, err := Get("http://127.0.0.1:1/", &RequestOptions{RateLimiter: limiter, Context: ctx}); err != context.Canceled {
+		t.Error("Expected the request to be cancelled, got: ", err)
+	}
+}
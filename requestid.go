@@ -0,0 +1,63 @@
+package grequests
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// idempotencyKeyHeader is the header that carries the idempotency key of a request
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// requestIDHeader is the header that carries the ID of a request
+	requestIDHeader = "X-Request-ID"
+)
+
+// unsafeMethods are the methods that are given an idempotency key
+var unsafeMethods = map[string]struct{}{
+	http.MethodPost:   {},
+	http.MethodPut:    {},
+	http.MethodPatch:  {},
+	http.MethodDelete: {},
+}
+
+// addRequestIDs generates the Idempotency-Key and X-Request-ID headers (unless they have already been set).
+// The IDs are generated once so retries of the request carry the same IDs
+func addRequestIDs(ro *RequestOptions, req *http.Request) error {
+	if _, unsafe := unsafeMethods[req.Method]; ro.IdempotencyKey && unsafe && req.Header.Get(idempotencyKeyHeader) == "" {
+		key, err := newUUID()
+
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+
+	if ro.RequestID && req.Header.Get(requestIDHeader) == "" {
+		id, err := newUUID()
+
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set(requestIDHeader, id)
+	}
+
+	return nil
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	var uuid [16]byte
+
+	if _, err := rand.Read(uuid[:]); err != nil {
+		return "", err
+	}
+
+	uuid[6] = uuid[6]&0x0f | 0x40 // Version 4
+	uuid[8] = uuid[8]&0x3f | 0x80 // Variant is 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:]), nil
+}
@@ -0,0 +1,72 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewUUID(t *testing.T) {
+	first, _ := newUUID()
+	second, _ := newUUID()
+
+	if !uuidPattern.MatchString(first) || first == second {
+		t.Error("Invalid UUIDs: ", first, second)
+	}
+}
+
+func TestIdempotencyKeyIsKeptOnRetry(t *testing.T) {
+	var keys, ids []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		ids = append(ids, r.Header.Get("X-Request-ID"))
+
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	resp, err := Post(ts.URL, &RequestOptions{
+		IdempotencyKey: true,
+		RequestID:      true,
+		RetryPolicy:    &BackoffRetryPolicy{Backoff: ConstantBackoff(0)},
+	})
+
+	if err != nil || !resp.Ok {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if len(keys) != 2 || keys[0] != keys[1] || !uuidPattern.MatchString(keys[0]) || resp.IdempotencyKey != keys[0] {
+		t.Error("Unexpected idempotency keys: ", keys, resp.IdempotencyKey)
+	}
+
+	if ids[0] != ids[1] || !uuidPattern.MatchString(ids[0]) || resp.RequestID != ids[0] {
+		t.Error("Unexpected request IDs: ", ids, resp.RequestID)
+	}
+}
+
+func TestIdempotencyKeySafeMethodsAndUserHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, &RequestOptions{IdempotencyKey: true})
+
+	if err != nil || resp.IdempotencyKey != "" || resp.RequestID != "" {
+		t.Error("A GET request was given an idempotency key: ", resp.IdempotencyKey, err)
+	}
+
+	resp, err = Put(ts.URL, &RequestOptions{
+		IdempotencyKey: true,
+		RequestID:      true,
+		Headers:        map[string]string{"Idempotency-Key": "mine", "X-Request-ID": "trace-1"},
+	})
+
+	if err != nil || resp.IdempotencyKey != "mine" || resp.RequestID != "trace-1" {
+		t.Error("The headers of the user were replaced: ", resp.IdempotencyKey, resp.RequestID, err)
+	}
+}
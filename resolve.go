@@ -0,0 +1,55 @@
+package grequests
+
+import (
+	"context"
+	"net"
+)
+
+// resolveAddress returns the address that should be dialed for addr ("host:port") according to
+// Resolve. "host:port" overrides take precedence over "host" overrides
+func (ro RequestOptions) resolveAddress(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+
+	if err != nil {
+		return addr
+	}
+
+	override, ok := ro.Resolve[addr]
+
+	if !ok {
+		if override, ok = ro.Resolve[host]; !ok {
+			return addr
+		}
+	}
+
+	// The override may change the port as well
+	if _, _, err := net.SplitHostPort(override); err == nil {
+		return override
+	}
+
+	return net.JoinHostPort(override, port)
+}
+
+// ipVersionDialer returns a dial function that only dials the IP version chosen by ForceIPv4 or
+// ForceIPv6 (dial is returned as is if neither of them is set)
+func (ro RequestOptions) ipVersionDialer(dial dialFunc) dialFunc {
+	var version string
+
+	switch {
+	case ro.ForceIPv4:
+		version = "4"
+	case ro.ForceIPv6:
+		version = "6"
+	default:
+		return dial
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		// Only TCP and UDP networks have an IP version (e.g. "tcp" becomes "tcp4")
+		if network == "tcp" || network == "udp" {
+			network += version
+		}
+
+		return dial(ctx, network, addr)
+	}
+}
@@ -0,0 +1,112 @@
+package grequests
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestResolveAddress(t *testing.T) {
+	ro := RequestOptions{Resolve: map[string]string{
+		"api.example.com:443": "10.0.0.1",
+		"api.example.com":     "10.0.0.2",
+		"staging.example.com": "10.0.0.3:8443",
+		"ipv6.example.com":    "::1",
+	}}
+
+	tests := map[string]string{
+		"api.example.com:443":     "10.0.0.1:443",
+		"api.example.com:80":      "10.0.0.2:80",
+		"staging.example.com:443": "10.0.0.3:8443",
+		"ipv6.example.com:80":     "[::1]:80",
+		"other.example.com:443":   "other.example.com:443",
+	}
+
+	for addr, expected := range tests {
+		if resolved := ro.resolveAddress(addr); resolved != expected {
+			t.Errorf("Expected %s to resolve to %s, got %s", addr, expected, resolved)
+		}
+	}
+}
+
+func TestResolveOverride(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host))
+	}))
+	defer ts.Close()
+
+	resp, err := Get("http://api.example.invalid/", &RequestOptions{
+		Resolve: map[string]string{"api.example.invalid": ts.Listener.Addr().String()},
+	})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if resp.String() != "api.example.invalid" {
+		t.Error("The Host header was not kept: ", resp.String())
+	}
+}
+
+func TestCustomResolver(t *testing.T) {
+	var dialed int32
+
+	// The resolver dials a fake DNS server – the lookup fails but proves the resolver is used
+	resolver := &net.Resolver{PreferGo: true, Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		atomic.StoreInt32(&dialed, 1)
+		return nil, &net.OpError{Op: "dial", Err: context.Canceled}
+	}}
+
+	if _, err := Get("http://api.example.invalid/", &RequestOptions{Resolver: resolver}); err == nil {
+		t.Fatal("Lookup with a broken resolver succeeded")
+	}
+
+	if atomic.LoadInt32(&dialed) == 0 {
+		t.Error("The custom resolver was not used")
+	}
+}
+
+func TestIPVersionDialer(t *testing.T) {
+	var dialed string
+
+	dial := func(_ context.Context, network, _ string) (net.Conn, error) {
+		dialed = network
+		return nil, nil
+	}
+
+	tests := []struct {
+		ro       RequestOptions
+		network  string
+		expected string
+	}{
+		{RequestOptions{}, "tcp", "tcp"},
+		{RequestOptions{ForceIPv4: true}, "tcp", "tcp4"},
+		{RequestOptions{ForceIPv6: true}, "tcp", "tcp6"},
+		{RequestOptions{ForceIPv4: true, ForceIPv6: true}, "tcp", "tcp4"},
+		{RequestOptions{ForceIPv6: true}, "unix", "unix"},
+	}
+
+	for _, test := range tests {
+		test.ro.ipVersionDialer(dial)(context.Background(), test.network, "example.com:80")
+
+		if dialed != test.expected {
+			t.Errorf("%+v dialed %q instead of %q", test.ro, dialed, test.expected)
+		}
+	}
+}
+
+func TestForceIPVersion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	if _, err := Get(ts.URL, &RequestOptions{ForceIPv4: true, HappyEyeballsDelay: -1}); err != nil {
+		t.Error("Unable to dial the IPv4 server: ", err)
+	}
+
+	if _, err := Get(ts.URL, &RequestOptions{ForceIPv6: true}); err == nil {
+		t.Error("IPv4 server was dialed with ForceIPv6")
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"time"
 )
 
 // Response is what is returned to a user when they fire off a request
@@ -28,7 +30,49 @@ type Response struct {
 	// Header is a net/http/Header structure
 	Header http.Header
 
+	// Proto is the protocol of the response e.g. "HTTP/1.1" or "HTTP/2.0"
+	Proto string
+
+	// Duration is the total amount of time spent sending the request (including any retries)
+	// until the response headers of the last attempt were received
+	Duration time.Duration
+
+	// Attempts is the amount of times the request was sent
+	Attempts int
+
+	// RetryHistory contains the outcome of every attempt (in order)
+	RetryHistory []Attempt
+
+	// IdempotencyKey is the Idempotency-Key header that was sent with the request (if any)
+	IdempotencyKey string
+
+	// RequestID is the X-Request-ID header that was sent with the request (if any)
+	RequestID string
+
+	// Timings is the breakdown of the time spent on the last attempt (DNS lookup, connect,
+	// TLS handshake, time to first byte and total)
+	Timings Timings
+
 	internalByteBuffer *bytes.Buffer
+
+	// encodedCounter and decodedCounter are set if we decoded the Content-Encoding of the body
+	encodedCounter *countingReader
+	decodedCounter *countingReader
+
+	// request is the request that produced the response (used by the RetryPolicy)
+	request *http.Request
+
+	// disallowUnknownFields makes .JSON() return an error for fields that aren't within the struct
+	disallowUnknownFields bool
+
+	// responseSchema (if set) is used to validate the body within .JSON()
+	responseSchema *JSONSchema
+
+	// disableCharsetDecoding makes .String() return the body as it was sent
+	disableCharsetDecoding bool
+
+	// utf8Body is the body converted into UTF-8 (it is only set when the body needed converting)
+	utf8Body []byte
 }
 
 func buildResponse(resp *http.Response, err error) (*Response, error) {
@@ -44,6 +88,7 @@ func buildResponse(resp *http.Response, err error) (*Response, error) {
 		RawResponse:        resp,
 		StatusCode:         resp.StatusCode,
 		Header:             resp.Header,
+		Proto:              resp.Proto,
 		internalByteBuffer: bytes.NewBuffer([]byte{}),
 	}, nil
 }
@@ -68,8 +113,18 @@ func (r *Response) Close() error {
 	return r.RawResponse.Body.Close()
 }
 
+// ProgressFunc is called as the body of a response is read. transferred is the amount of bytes that have been read
+// so far and total is the size of the body (from the Content-Length header) or -1 if it isn't known
+type ProgressFunc func(transferred, total int64)
+
 // DownloadToFile allows you to download the contents of the response to a file
 func (r *Response) DownloadToFile(fileName string) error {
+	return r.DownloadToFileWithProgress(fileName, nil)
+}
+
+// DownloadToFileWithProgress works like DownloadToFile except that progress (if not nil) is called
+// as the body is written to the file
+func (r *Response) DownloadToFileWithProgress(fileName string, progress ProgressFunc) error {
 
 	if r.Error != nil {
 		return r.Error
@@ -84,13 +139,45 @@ func (r *Response) DownloadToFile(fileName string) error {
 	defer r.Close() // This is a noop if we use the internal ByteBuffer
 	defer fd.Close()
 
-	if _, err := io.Copy(fd, r.getInternalReader()); err != nil && err != io.EOF {
+	if _, err := io.Copy(fd, r.progressReader(progress)); err != nil && err != io.EOF {
 		return err
 	}
 
 	return nil
 }
 
+// BodyReader returns a reader that streams the body of the response (the body is never held in memory).
+// progress (if not nil) is called as the body is read. The reader must be closed once you are done with it
+func (r *Response) BodyReader(progress ProgressFunc) io.ReadCloser {
+	return readCloser{Reader: r.progressReader(progress), Closer: r}
+}
+
+// progressReader returns the internal reader of the response which reports its progress
+func (r *Response) progressReader(progress ProgressFunc) io.Reader {
+	reader := r.getInternalReader()
+
+	if progress == nil {
+		return reader
+	}
+
+	total := int64(-1)
+
+	if r.RawResponse != nil {
+		total = r.RawResponse.ContentLength
+	}
+
+	if reader == r.internalByteBuffer {
+		total = int64(r.internalByteBuffer.Len())
+	}
+
+	var transferred int64
+
+	return &progressReader{Reader: reader, progress: func(n int64) {
+		transferred += n
+		progress(transferred, total)
+	}}
+}
+
 // getInternalReader because we implement io.ReadCloser and optionally hold a large buffer of the response (created by
 // the user's request)
 func (r *Response) getInternalReader() io.Reader {
@@ -109,14 +196,19 @@ func (r *Response) XML(userStruct interface{}, charsetReader XMLCharDecoder) err
 		return r.Error
 	}
 
-	xmlDecoder := xml.NewDecoder(r.getInternalReader())
+	defer r.Close()
+
+	return decodeXML(r.getInternalReader(), userStruct, charsetReader)
+}
+
+// decodeXML decodes the XML into userStruct
+func decodeXML(reader io.Reader, userStruct interface{}, charsetReader XMLCharDecoder) error {
+	xmlDecoder := xml.NewDecoder(reader)
 
 	if charsetReader != nil {
 		xmlDecoder.CharsetReader = charsetReader
 	}
 
-	defer r.Close()
-
 	if err := xmlDecoder.Decode(&userStruct); err != nil && err != io.EOF {
 		return err
 	}
@@ -132,9 +224,33 @@ func (r *Response) JSON(userStruct interface{}) error {
 		return r.Error
 	}
 
-	jsonDecoder := json.NewDecoder(r.getInternalReader())
 	defer r.Close()
 
+	return r.decodeJSON(r.getInternalReader(), userStruct)
+}
+
+// decodeJSON validates the JSON against the response schema (if there is one) and decodes it into userStruct
+func (r *Response) decodeJSON(reader io.Reader, userStruct interface{}) error {
+	if r.responseSchema != nil {
+		body, err := ioutil.ReadAll(reader)
+
+		if err != nil {
+			return err
+		}
+
+		if err := r.responseSchema.Validate(body); err != nil {
+			return err
+		}
+
+		reader = bytes.NewReader(body)
+	}
+
+	jsonDecoder := json.NewDecoder(reader)
+
+	if r.disallowUnknownFields {
+		jsonDecoder.DisallowUnknownFields()
+	}
+
 	if err := jsonDecoder.Decode(&userStruct); err != nil && err != io.EOF {
 		return err
 	}
@@ -142,6 +258,19 @@ func (r *Response) JSON(userStruct interface{}) error {
 	return nil
 }
 
+// ValidateJSONSchema validates the body of the response against the JSON Schema. If the body doesn't
+// match the schema a *SchemaValidationError is returned. The body is held within the internal buffer
+// (just like .Bytes()) so it can still be decoded afterwards
+func (r *Response) ValidateJSONSchema(schema *JSONSchema) error {
+	body := r.Bytes()
+
+	if r.Error != nil {
+		return r.Error
+	}
+
+	return schema.Validate(body)
+}
+
 // createResponseBytesBuffer is a utility method that will populate the internal byte reader – this is largely used for .String()
 // and .Bytes()
 func (r *Response) populateResponseByteBuffer() {
@@ -186,7 +315,8 @@ func (r *Response) Bytes() []byte {
 
 }
 
-// String returns the response as a string
+// String returns the response as a string. Text bodies that aren't encoded in UTF-8 are converted
+// into UTF-8 (see DisableCharsetDecoding)
 func (r *Response) String() string {
 	if r.Error != nil {
 		return ""
@@ -194,7 +324,27 @@ func (r *Response) String() string {
 
 	r.populateResponseByteBuffer()
 
-	return r.internalByteBuffer.String()
+	return string(r.textBody())
+}
+
+// EncodedSize returns the amount of (still encoded) bytes that have been read from the wire. If the
+// body wasn't encoded (or was transparently decoded by the net/http transport) -1 is returned
+func (r *Response) EncodedSize() int64 {
+	if r.encodedCounter == nil {
+		return -1
+	}
+
+	return r.encodedCounter.n
+}
+
+// DecodedSize returns the amount of decoded bytes that have been read from the body. If the body
+// wasn't encoded (or was transparently decoded by the net/http transport) -1 is returned
+func (r *Response) DecodedSize() int64 {
+	if r.decodedCounter == nil {
+		return -1
+	}
+
+	return r.decodedCounter.n
 }
 
 // ClearInternalBuffer is a function that will clear the internal buffer that we use to hold the .String() and .Bytes()
@@ -206,4 +356,5 @@ func (r *Response) ClearInternalBuffer() {
 	}
 
 	r.internalByteBuffer.Reset()
+	r.utf8Body = nil
 }
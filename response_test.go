@@ -1,7 +1,12 @@
 package grequests
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -24,3 +29,73 @@ func verifyResponseOkForStatus(status int, t *testing.T) {
 		t.Errorf("Request did not return OK. Received status code %d rather a 2xx.", resp.StatusCode)
 	}
 }
+
+func TestDownloadToFileWithProgress(t *testing.T) {
+	body := strings.Repeat("grequests", 10000)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, &RequestOptions{DisableCompression: true})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	var transferred, total int64
+
+	path := filepath.Join(t.TempDir(), "download")
+
+	err = resp.DownloadToFileWithProgress(path, func(n, size int64) {
+		if n < transferred {
+			t.Error("Progress went backwards")
+		}
+		transferred, total = n, size
+	})
+
+	if err != nil {
+		t.Fatal("Unable to download file: ", err)
+	}
+
+	if transferred != int64(len(body)) || total != int64(len(body)) {
+		t.Errorf("Invalid progress: %d/%d", transferred, total)
+	}
+
+	if b, _ := ioutil.ReadFile(path); string(b) != body {
+		t.Error("File contents are invalid")
+	}
+}
+
+func TestBodyReader(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("streamed body"))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	var transferred int64
+
+	reader := resp.BodyReader(func(n, total int64) { transferred = n })
+
+	b, err := ioutil.ReadAll(reader)
+
+	if err != nil || string(b) != "streamed body" {
+		t.Error("Body was not streamed: ", string(b), err)
+	}
+
+	if transferred != int64(len("streamed body")) {
+		t.Error("Progress was not reported: ", transferred)
+	}
+
+	if err := reader.Close(); err != nil {
+		t.Error("Unable to close body: ", err)
+	}
+}
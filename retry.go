@@ -0,0 +1,336 @@
+package grequests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// maxDrainBytes is the maximum amount of bytes we will read from the body of a response
+// that is being discarded (so the connection can be reused)
+const maxDrainBytes = 4096
+
+// RetryPolicy decides if a request should be retried. ShouldRetry is called after
+// every attempt with the response (which may contain an error) and the amount of
+// attempts that have been made so far (starting at 1). If retry is true the request
+// will be sent again after waiting for delay.
+//
+// The policy may read the body of the response (e.g. to look for an error code in
+// the JSON body). When the request is not retried the same *Response is returned to the user
+type RetryPolicy interface {
+	ShouldRetry(resp *Response, err error, attempt int) (delay time.Duration, retry bool)
+}
+
+// RetryPolicyFunc is an adapter to allow the use of an ordinary function as a RetryPolicy
+type RetryPolicyFunc func(resp *Response, err error, attempt int) (time.Duration, bool)
+
+// ShouldRetry calls f(resp, err, attempt)
+func (f RetryPolicyFunc) ShouldRetry(resp *Response, err error, attempt int) (time.Duration, bool) {
+	return f(resp, err, attempt)
+}
+
+const (
+	// defaultRetryAttempts is the amount of attempts BackoffRetryPolicy makes by default
+	defaultRetryAttempts = 3
+
+	// defaultMaxRetryAfter is the longest Retry-After BackoffRetryPolicy will wait for by default
+	defaultMaxRetryAfter = time.Minute
+)
+
+// defaultRetryableStatusCodes are the status codes BackoffRetryPolicy retries by default
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// BackoffRetryPolicy is a RetryPolicy that retries network errors and retryable status codes
+// with exponential backoff. A Retry-After header sent by the server is respected. By default only
+// idempotent requests (GET, HEAD, OPTIONS, TRACE, PUT and DELETE or a request with an
+// Idempotency-Key header) are retried
+type BackoffRetryPolicy struct {
+	// MaxAttempts is the maximum amount of attempts (including the first one). Defaults to 3
+	MaxAttempts int
+
+	// RetryableStatusCodes are the status codes that are retried. Defaults to 429, 502, 503 and 504
+	RetryableStatusCodes []int
+
+	// Backoff returns the delay before the next attempt. Defaults to ExponentialBackoff(100ms, 10s)
+	Backoff Backoff
+
+	// MaxRetryAfter is the longest Retry-After we will wait for. If the server asks us to wait
+	// longer the response is returned. Defaults to one minute
+	MaxRetryAfter time.Duration
+
+	// RetryNonIdempotent allows requests with non idempotent methods (e.g. POST) to be retried
+	RetryNonIdempotent bool
+}
+
+// ShouldRetry implements RetryPolicy
+func (p *BackoffRetryPolicy) ShouldRetry(resp *Response, err error, attempt int) (time.Duration, bool) {
+	maxAttempts := p.MaxAttempts
+
+	if maxAttempts == 0 {
+		maxAttempts = defaultRetryAttempts
+	}
+
+	// A response that is too large will be too large again
+	if attempt >= maxAttempts || errors.Is(err, context.Canceled) || errors.Is(err, ErrResponseBodyTooLarge) {
+		return 0, false
+	}
+
+	if !p.RetryNonIdempotent && resp.request != nil && !isIdempotent(resp.request) {
+		return 0, false
+	}
+
+	if err == nil && !p.retryableStatus(resp.StatusCode) {
+		return 0, false
+	}
+
+	if err == nil {
+		if delay, ok := parseRetryAfter(resp.Header); ok {
+			maxRetryAfter := p.MaxRetryAfter
+
+			if maxRetryAfter == 0 {
+				maxRetryAfter = defaultMaxRetryAfter
+			}
+
+			return delay, delay <= maxRetryAfter
+		}
+	}
+
+	backoff := p.Backoff
+
+	if backoff == nil {
+		backoff = ExponentialBackoff(100*time.Millisecond, 10*time.Second)
+	}
+
+	return backoff(attempt), true
+}
+
+// retryableStatus reports if the status code should be retried
+func (p *BackoffRetryPolicy) retryableStatus(statusCode int) bool {
+	statusCodes := p.RetryableStatusCodes
+
+	if statusCodes == nil {
+		statusCodes = defaultRetryableStatusCodes
+	}
+
+	for _, code := range statusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isIdempotent reports if sending the request more than once has the same effect as sending it once
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+
+	return req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+// Attempt is the outcome of a single attempt at sending a request
+type Attempt struct {
+	// StatusCode is the HTTP status code returned by the attempt (0 if the attempt failed)
+	StatusCode int
+
+	// Error is the error returned by the attempt (if any)
+	Error error
+
+	// Duration is how long the attempt took (until the response headers were received)
+	Duration time.Duration
+
+	// Delay is how long we waited after the attempt before retrying the request
+	Delay time.Duration
+}
+
+// sendRequest sends the request – retrying it for as long as the RetryPolicy allows
+func sendRequest(httpClient *http.Client, req *http.Request, ro *RequestOptions) (*Response, error) {
+	start := time.Now()
+
+	ctx, cancelOverall := req.Context(), context.CancelFunc(func() {})
+
+	if ro.OverallDeadline > 0 {
+		ctx, cancelOverall = context.WithTimeout(ctx, ro.OverallDeadline)
+	}
+
+	var history []Attempt
+
+	for attempt := 1; ; attempt++ {
+		if ro.RateLimiter != nil {
+			if err := ro.RateLimiter.Wait(ctx, req); err != nil {
+				cancelOverall()
+				closeRequestBody(req)
+				return buildResponse(nil, err)
+			}
+		}
+
+		// Signatures and tokens are renewed for every attempt
+		if err := authorizeRequest(ro, req); err != nil {
+			cancelOverall()
+			closeRequestBody(req)
+			return buildResponse(nil, err)
+		}
+
+		attemptStart := time.Now()
+
+		attemptCtx, timing := newTimingTrace(ctx)
+
+		resp, err := buildResponse(sendAttempt(attemptCtx, httpClient, req, ro.AttemptTimeout))
+
+		timing.finish(resp)
+
+		statusCode := resp.StatusCode
+
+		if err == nil {
+			if err = decodeResponseBody(resp); err == nil {
+				err = limitResponseBody(resp, ro)
+			}
+
+			if err == nil {
+				err = verifyResponseChecksum(resp, ro)
+			}
+
+			// The response is replaced by the error so nothing else will close its body
+			if err != nil {
+				resp.RawResponse.Body.Close()
+				resp = &Response{Error: err}
+			}
+		}
+
+		resp.request = req
+
+		history = append(history, Attempt{StatusCode: statusCode, Error: err, Duration: time.Since(attemptStart)})
+
+		var (
+			delay time.Duration
+			retry bool
+		)
+
+		if ro.RetryPolicy != nil {
+			delay, retry = ro.RetryPolicy.ShouldRetry(resp, err, attempt)
+		}
+
+		if !retry || !canRewindBody(req) || exceedsDeadline(ctx, delay) {
+			resp.Duration = time.Since(start)
+			resp.Attempts = attempt
+			resp.RetryHistory = history
+
+			// The overall deadline must remain in place until the user is done with the body
+			if err != nil {
+				cancelOverall()
+			} else {
+				resp.RawResponse.Body = &cancelOnClose{ReadCloser: resp.RawResponse.Body, cancel: cancelOverall}
+			}
+
+			return resp, err
+		}
+
+		history[len(history)-1].Delay = delay
+
+		discardResponse(resp)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			cancelOverall()
+			return buildResponse(nil, wrapTimeout(ctx.Err()))
+		}
+
+		if err := rewindBody(req); err != nil {
+			cancelOverall()
+			return buildResponse(nil, err)
+		}
+	}
+}
+
+// sendAttempt sends a single attempt of the request. If attemptTimeout is set the attempt
+// (including reading the body) must complete within the timeout
+func sendAttempt(ctx context.Context, httpClient *http.Client, req *http.Request, attemptTimeout time.Duration) (*http.Response, error) {
+	if attemptTimeout <= 0 {
+		resp, err := httpClient.Do(req.WithContext(ctx))
+		return resp, wrapTimeout(err)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, attemptTimeout)
+
+	resp, err := httpClient.Do(req.WithContext(attemptCtx))
+
+	if err != nil {
+		cancel()
+		return nil, wrapTimeout(err)
+	}
+
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// exceedsDeadline reports if waiting for delay would take us past the deadline of the context
+func exceedsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+
+	return ok && time.Now().Add(delay).After(deadline)
+}
+
+// cancelOnClose cancels the context of the request once the body has been closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// canRewindBody reports if we are able to send the body of the request again
+func canRewindBody(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewindBody resets the body of the request so it can be sent again
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil || req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return err
+	}
+
+	req.Body = body
+
+	return nil
+}
+
+// closeRequestBody closes the body of a request that won't be sent (the transport closes the body of
+// the requests it sends) so that the files of an upload aren't leaked
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// discardResponse drains (a bit of) and closes the body of a response that won't be returned to the user
+func discardResponse(resp *Response) {
+	if resp.Error != nil || resp.RawResponse == nil {
+		return
+	}
+
+	io.Copy(ioutil.Discard, io.LimitReader(resp.RawResponse.Body, maxDrainBytes))
+	resp.RawResponse.Body.Close()
+}
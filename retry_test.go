@@ -0,0 +1,271 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyRetriesRequest(t *testing.T) {
+	var hits int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			w.Write([]byte(`{"error":"rate_limited"}`))
+			return
+		}
+		w.Write([]byte(`{"error":""}`))
+	}))
+	defer ts.Close()
+
+	policy := RetryPolicyFunc(func(resp *Response, err error, attempt int) (time.Duration, bool) {
+		if err != nil || attempt >= 5 {
+			return 0, false
+		}
+
+		body := struct{ Error string }{}
+
+		if err := resp.JSON(&body); err != nil {
+			return 0, false
+		}
+
+		return time.Millisecond, body.Error == "rate_limited"
+	})
+
+	resp, err := Post(ts.URL, &RequestOptions{JSON: map[string]string{"One": "Two"}, RetryPolicy: policy})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if atomic.LoadInt32(&hits) != 3 {
+		t.Error("Request was not retried: ", hits)
+	}
+
+	if !resp.Ok {
+		t.Error("Final response is not OK: ", resp.StatusCode)
+	}
+}
+
+func TestRetryPolicyReplaysBody(t *testing.T) {
+	var bodies []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		bodies = append(bodies, r.PostForm.Get("One"))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	policy := RetryPolicyFunc(func(resp *Response, err error, attempt int) (time.Duration, bool) {
+		return 0, attempt < 2
+	})
+
+	resp, err := Post(ts.URL, &RequestOptions{Data: map[string]string{"One": "Two"}, RetryPolicy: policy})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Error("Last response was not returned: ", resp.StatusCode)
+	}
+
+	if len(bodies) != 2 || bodies[0] != "Two" || bodies[1] != "Two" {
+		t.Error("Body was not replayed: ", bodies)
+	}
+}
+
+func TestRetryHistoryIsRecorded(t *testing.T) {
+	var hits int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+
+	policy := RetryPolicyFunc(func(resp *Response, err error, attempt int) (time.Duration, bool) {
+		return 5 * time.Millisecond, resp.StatusCode == http.StatusBadGateway
+	})
+
+	resp, err := Get(ts.URL, &RequestOptions{RetryPolicy: policy})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.Attempts != 2 || len(resp.RetryHistory) != 2 {
+		t.Fatal("Attempts were not recorded: ", resp.Attempts, resp.RetryHistory)
+	}
+
+	if resp.RetryHistory[0].StatusCode != http.StatusBadGateway || resp.RetryHistory[0].Delay != 5*time.Millisecond {
+		t.Error("First attempt was not recorded: ", resp.RetryHistory[0])
+	}
+
+	if resp.RetryHistory[1].StatusCode != http.StatusOK {
+		t.Error("Second attempt was not recorded: ", resp.RetryHistory[1])
+	}
+
+	if resp.Duration < 5*time.Millisecond {
+		t.Error("Duration does not include the retry delay: ", resp.Duration)
+	}
+}
+
+func TestAttemptTimeoutRetriesSlowAttempt(t *testing.T) {
+	var hits int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte("done"))
+	}))
+	defer ts.Close()
+
+	policy := RetryPolicyFunc(func(resp *Response, err error, attempt int) (time.Duration, bool) {
+		return 0, err != nil && attempt < 3
+	})
+
+	resp, err := Get(ts.URL, &RequestOptions{RetryPolicy: policy, AttemptTimeout: 50 * time.Millisecond})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.Attempts != 2 || resp.RetryHistory[0].Error == nil {
+		t.Error("Slow attempt was not timed out: ", resp.RetryHistory)
+	}
+
+	if resp.String() != "done" {
+		t.Error("Body could not be read after the attempt: ", resp.String())
+	}
+}
+
+func TestOverallDeadlineStopsRetries(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	policy := RetryPolicyFunc(func(resp *Response, err error, attempt int) (time.Duration, bool) {
+		return 20 * time.Millisecond, true
+	})
+
+	start := time.Now()
+
+	resp, _ := Get(ts.URL, &RequestOptions{RetryPolicy: policy, OverallDeadline: 100 * time.Millisecond})
+
+	if time.Since(start) > time.Second {
+		t.Error("Overall deadline was not enforced: ", time.Since(start))
+	}
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Error("Last response was not returned: ", resp.StatusCode)
+	}
+}
+
+func TestBackoffRetryPolicyRetriesStatusCodes(t *testing.T) {
+	var hits int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	policy := &BackoffRetryPolicy{Backoff: ConstantBackoff(time.Millisecond)}
+
+	resp, err := Get(ts.URL, &RequestOptions{RetryPolicy: policy})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || resp.Attempts != 3 {
+		t.Errorf("Expected success after 3 attempts, got %d after %d", resp.StatusCode, resp.Attempts)
+	}
+}
+
+func TestBackoffRetryPolicyMaxAttempts(t *testing.T) {
+	var hits int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	policy := &BackoffRetryPolicy{MaxAttempts: 2, Backoff: ConstantBackoff(time.Millisecond)}
+
+	resp, err := Get(ts.URL, &RequestOptions{RetryPolicy: policy})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.StatusCode != http.StatusBadGateway || atomic.LoadInt32(&hits) != 2 {
+		t.Errorf("Expected 2 attempts, got %d (status %d)", hits, resp.StatusCode)
+	}
+}
+
+func TestBackoffRetryPolicyIdempotency(t *testing.T) {
+	var hits int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	policy := &BackoffRetryPolicy{Backoff: ConstantBackoff(time.Millisecond)}
+
+	if _, err := Post(ts.URL, &RequestOptions{RetryPolicy: policy}); err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Error("POST was retried: ", n)
+	}
+
+	_, err := Post(ts.URL, &RequestOptions{
+		RetryPolicy: policy,
+		Headers:     map[string]string{"Idempotency-Key": "abc"},
+	})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if n := atomic.LoadInt32(&hits); n != 4 {
+		t.Error("POST with an Idempotency-Key was not retried: ", n)
+	}
+}
+
+func TestBackoffRetryPolicyRetryAfter(t *testing.T) {
+	policy := &BackoffRetryPolicy{MaxRetryAfter: 10 * time.Second}
+
+	resp := &Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"5"}}}
+
+	if delay, retry := policy.ShouldRetry(resp, nil, 1); !retry || delay != 5*time.Second {
+		t.Errorf("Retry-After was not respected: %v %v", delay, retry)
+	}
+
+	resp.Header.Set("Retry-After", "60")
+
+	if _, retry := policy.ShouldRetry(resp, nil, 1); retry {
+		t.Error("Retry-After longer than MaxRetryAfter was retried")
+	}
+
+	resp.StatusCode = http.StatusBadRequest
+
+	if _, retry := policy.ShouldRetry(resp, nil, 1); retry {
+		t.Error("Non retryable status code was retried")
+	}
+}
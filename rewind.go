@@ -0,0 +1,89 @@
+package grequests
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// bodyReopener returns a function that returns a new reader of the (remaining) contents of r so
+// they can be sent again (when the request is retried or redirected). nil is returned if the
+// contents can only be read once. Regular files are reopened (so every reader can be closed by
+// the transport) and any other io.ReaderAt of a known size is read using an io.SectionReader
+func bodyReopener(r io.Reader) func() (io.ReadCloser, error) {
+	size := readerSize(r)
+
+	if size < 0 {
+		return nil
+	}
+
+	switch v := r.(type) {
+	case *os.File:
+		offset, err := v.Seek(0, io.SeekCurrent)
+
+		if err != nil {
+			return nil
+		}
+
+		name := v.Name()
+
+		return func() (io.ReadCloser, error) {
+			fd, err := os.Open(name)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := fd.Seek(offset, io.SeekStart); err != nil {
+				fd.Close()
+				return nil, err
+			}
+
+			return readCloser{Reader: io.LimitReader(fd, size), Closer: fd}, nil
+		}
+	case io.ReaderAt:
+		var offset int64
+
+		if seeker, ok := r.(io.Seeker); ok {
+			var err error
+
+			if offset, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+				return nil
+			}
+		}
+
+		return func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(io.NewSectionReader(v, offset, size)), nil
+		}
+	}
+
+	return nil
+}
+
+// multipartGetBody returns the GetBody function of a multipart body which reopens the contents
+// of every section. nil is returned if the contents of a section can only be read once
+func multipartGetBody(sections []multipartSection, boundary string) func() (io.ReadCloser, error) {
+	for _, section := range sections {
+		if section.reopen == nil {
+			return nil
+		}
+	}
+
+	return func() (io.ReadCloser, error) {
+		reopened := make([]multipartSection, 0, len(sections))
+
+		for _, section := range sections {
+			contents, err := section.reopen()
+
+			if err != nil {
+				closeSections(reopened)
+				return nil, err
+			}
+
+			section.contents = contents
+			reopened = append(reopened, section)
+		}
+
+		return &multipartStream{sections: reopened, boundary: boundary}, nil
+	}
+}
@@ -0,0 +1,137 @@
+package grequests
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetryResendsFileBody(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "body.txt")
+
+	if err := ioutil.WriteFile(fileName, []byte("skip:file contents"), 0644); err != nil {
+		t.Fatal("Unable to create file: ", err)
+	}
+
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		attempt := len(bodies)
+		mu.Unlock()
+
+		if attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	fd, err := os.Open(fileName)
+
+	if err != nil {
+		t.Fatal("Unable to open file: ", err)
+	}
+
+	// Only the rest of the file is sent
+	if _, err := fd.Seek(5, 0); err != nil {
+		t.Fatal("Unable to seek file: ", err)
+	}
+
+	policy := RetryPolicyFunc(func(resp *Response, err error, attempt int) (time.Duration, bool) {
+		return time.Millisecond, err == nil && resp.StatusCode == http.StatusServiceUnavailable
+	})
+
+	resp, err := Post(ts.URL, &RequestOptions{RequestBody: fd, RetryPolicy: policy})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if !resp.Ok || len(bodies) != 2 || bodies[0] != "file contents" || bodies[1] != "file contents" {
+		t.Errorf("Body was not resent: %d %q", resp.StatusCode, bodies)
+	}
+}
+
+func TestRedirectResendsMultipartBody(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "upload.txt")
+
+	if err := ioutil.WriteFile(fileName, []byte("upload contents"), 0644); err != nil {
+		t.Fatal("Unable to create file: ", err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusPermanentRedirect)
+			return
+		}
+
+		file, _, err := r.FormFile("file")
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		defer file.Close()
+
+		contents, _ := ioutil.ReadAll(file)
+		w.Write([]byte(r.FormValue("name") + " " + string(contents)))
+	}))
+	defer ts.Close()
+
+	files, err := FileUploadFromDisk(fileName)
+
+	if err != nil {
+		t.Fatal("Unable to open file: ", err)
+	}
+
+	resp, err := Post(ts.URL+"/old", &RequestOptions{Files: files, Data: map[string]string{"name": "levi"}})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if body := resp.String(); body != "levi upload contents" {
+		t.Errorf("Body was not resent on redirect: %d %q", resp.StatusCode, body)
+	}
+}
+
+func TestBodyReopener(t *testing.T) {
+	if bodyReopener(strings.NewReader("abc")) == nil || bodyReopener(bytes.NewReader([]byte("abc"))) == nil {
+		t.Error("In memory readers can't be reopened")
+	}
+
+	if bodyReopener(&bytes.Buffer{}) != nil || bodyReopener(ioutil.NopCloser(strings.NewReader("abc"))) != nil {
+		t.Error("Readers that can only be read once were reopened")
+	}
+
+	reader := strings.NewReader("abcdef")
+	reader.Seek(2, 0)
+
+	reopen := bodyReopener(reader)
+
+	ioutil.ReadAll(reader)
+
+	body, err := reopen()
+
+	if err != nil {
+		t.Fatal("Unable to reopen: ", err)
+	}
+
+	if contents, _ := ioutil.ReadAll(body); string(contents) != "cdef" {
+		t.Error("Reopened reader didn't start at the offset: ", string(contents))
+	}
+}
@@ -0,0 +1,83 @@
+package grequests
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// ScanBody decodes the body of the response into v based upon the Content-Type of the response. JSON
+// (application/json and any +json type) is decoded using .JSON() and XML (application/xml, text/xml and
+// any +xml type) using .XML(). Other content types are decoded by the BodyDecoder registered for them
+// (see RegisterBodyDecoder) and may otherwise be scanned into a *string or *[]byte.
+// Bodies that aren't encoded in UTF-8 are converted using the charset of the Content-Type (or, for XML,
+// the encoding of the XML declaration)
+func (r *Response) ScanBody(v interface{}) error {
+	if r.Error != nil {
+		return r.Error
+	}
+
+	defer r.Close()
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	reader, err := utf8Reader(r.getInternalReader(), params["charset"])
+
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case isJSONMediaType(mediaType):
+		return r.decodeJSON(reader, v)
+	case isXMLMediaType(mediaType):
+		charsetReader := XMLCharDecoder(charset.NewReaderLabel)
+
+		// The body has already been converted so the encoding within the XML declaration must be ignored
+		if params["charset"] != "" {
+			charsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+		}
+
+		return decodeXML(reader, v, charsetReader)
+	}
+
+	if decoder, ok := lookupBodyDecoder(mediaType); ok {
+		return decoder(reader, v)
+	}
+
+	switch dst := v.(type) {
+	case *string:
+		body, err := ioutil.ReadAll(reader)
+		*dst = string(body)
+		return err
+	case *[]byte:
+		body, err := ioutil.ReadAll(reader)
+		*dst = body
+		return err
+	}
+
+	return fmt.Errorf("grequests: Unable to scan a body with the Content-Type %q into %T", mediaType, v)
+}
+
+// utf8Reader converts the reader from the charset into UTF-8
+func utf8Reader(reader io.Reader, charsetLabel string) (io.Reader, error) {
+	if charsetLabel == "" || strings.EqualFold(charsetLabel, "utf-8") || strings.EqualFold(charsetLabel, "utf8") {
+		return reader, nil
+	}
+
+	return charset.NewReaderLabel(charsetLabel, reader)
+}
+
+// isJSONMediaType reports if the media type is JSON
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// isXMLMediaType reports if the media type is XML
+func isXMLMediaType(mediaType string) bool {
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
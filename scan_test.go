@@ -0,0 +1,109 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newContentTypeServer(contentType, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write([]byte(body))
+	}))
+}
+
+type scanName struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func TestScanBody(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+	}{
+		{"application/json", `{"name":"café"}`},
+		{"application/problem+json; charset=utf-8", `{"name":"café"}`},
+		{"application/json; charset=iso-8859-1", "{\"name\":\"caf\xe9\"}"},
+		{"text/xml; charset=iso-8859-1", "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><r><name>caf\xe9</name></r>"},
+		{"application/atom+xml", "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><r><name>caf\xe9</name></r>"},
+		{"application/xml", "<r><name>café</name></r>"},
+	}
+
+	for _, test := range tests {
+		ts := newContentTypeServer(test.contentType, test.body)
+
+		resp, err := Get(ts.URL, nil)
+
+		if err != nil {
+			t.Fatal("Request failed: ", err)
+		}
+
+		var v scanName
+
+		if err := resp.ScanBody(&v); err != nil {
+			t.Errorf("%s: unable to scan body: %v", test.contentType, err)
+		} else if v.Name != "café" {
+			t.Errorf("%s: body was not decoded: %q", test.contentType, v.Name)
+		}
+
+		ts.Close()
+	}
+}
+
+func TestScanBodyText(t *testing.T) {
+	ts := newContentTypeServer("text/plain; charset=iso-8859-1", "caf\xe9")
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	var body string
+
+	if err := resp.ScanBody(&body); err != nil || body != "café" {
+		t.Error("Text body was not scanned: ", body, err)
+	}
+}
+
+func TestScanBodyUnsupportedContentType(t *testing.T) {
+	ts := newContentTypeServer("image/png", "png")
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if err := resp.ScanBody(&scanName{}); err == nil {
+		t.Error("An image was scanned into a struct")
+	}
+}
+
+func TestDisallowUnknownFields(t *testing.T) {
+	ts := newContentTypeServer("application/json", `{"name":"levi","age":30}`)
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if err := resp.JSON(&scanName{}); err != nil {
+		t.Error("Unknown fields were rejected by default: ", err)
+	}
+
+	resp, err = Get(ts.URL, &RequestOptions{DisallowUnknownFields: true})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if err := resp.JSON(&scanName{}); err == nil {
+		t.Error("Unknown field was accepted")
+	}
+}
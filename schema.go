@@ -0,0 +1,107 @@
+package grequests
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// jsonSchemaResource is the name the schema is registered under within the compiler
+const jsonSchemaResource = "grequests-response-schema.json"
+
+// JSONSchema is a compiled JSON Schema that response bodies can be validated against
+type JSONSchema struct {
+	schema *jsonschema.Schema
+}
+
+// SchemaViolation is a single reason why a JSON document didn't match the schema
+type SchemaViolation struct {
+	// InstanceLocation is the JSON pointer of the invalid value within the document
+	InstanceLocation string
+
+	// KeywordLocation is the JSON pointer of the schema keyword that failed
+	KeywordLocation string
+
+	// Message describes the violation
+	Message string
+}
+
+// SchemaValidationError is the error returned when a response body doesn't match the JSON Schema
+type SchemaValidationError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+
+	for _, v := range e.Violations {
+		messages = append(messages, fmt.Sprintf("%s: %s", v.InstanceLocation, v.Message))
+	}
+
+	return "grequests: Response body does not match the JSON Schema: " + strings.Join(messages, "; ")
+}
+
+// CompileJSONSchema compiles a JSON Schema. Schemas without a "$schema" keyword are treated
+// as draft 2020-12
+func CompileJSONSchema(schema []byte) (*JSONSchema, error) {
+	document, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+
+	if err := compiler.AddResource(jsonSchemaResource, document); err != nil {
+		return nil, err
+	}
+
+	compiledSchema, err := compiler.Compile(jsonSchemaResource)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONSchema{schema: compiledSchema}, nil
+}
+
+// Validate validates the JSON document against the schema. If the document doesn't match the
+// schema a *SchemaValidationError is returned
+func (s *JSONSchema) Validate(document []byte) error {
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(document))
+
+	if err != nil {
+		return err
+	}
+
+	err = s.schema.Validate(instance)
+
+	validationErr, ok := err.(*jsonschema.ValidationError)
+
+	if !ok {
+		return err
+	}
+
+	schemaErr := &SchemaValidationError{}
+
+	collectSchemaViolations(validationErr.BasicOutput(), schemaErr)
+
+	return schemaErr
+}
+
+func collectSchemaViolations(unit *jsonschema.OutputUnit, schemaErr *SchemaValidationError) {
+	if unit.Error != nil {
+		schemaErr.Violations = append(schemaErr.Violations, SchemaViolation{
+			InstanceLocation: unit.InstanceLocation,
+			KeywordLocation:  unit.KeywordLocation,
+			Message:          unit.Error.String(),
+		})
+	}
+
+	for i := range unit.Errors {
+		collectSchemaViolations(&unit.Errors[i], schemaErr)
+	}
+}
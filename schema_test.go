@@ -0,0 +1,91 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testUserSchema = `{
+	"type": "object",
+	"properties": {
+		"id": {"type": "integer"},
+		"name": {"type": "string"}
+	},
+	"required": ["id", "name"]
+}`
+
+func TestResponseSchemaValid(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 1, "name": "levi"}`))
+	}))
+	defer ts.Close()
+
+	schema, err := CompileJSONSchema([]byte(testUserSchema))
+
+	if err != nil {
+		t.Fatal("Unable to compile schema: ", err)
+	}
+
+	resp, err := Get(ts.URL, &RequestOptions{ResponseSchema: schema})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	user := struct {
+		ID   int
+		Name string
+	}{}
+
+	if err := resp.JSON(&user); err != nil {
+		t.Fatal("Valid body was rejected: ", err)
+	}
+
+	if user.ID != 1 || user.Name != "levi" {
+		t.Error("Body was not decoded: ", user)
+	}
+}
+
+func TestResponseSchemaInvalid(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer ts.Close()
+
+	schema, err := CompileJSONSchema([]byte(testUserSchema))
+
+	if err != nil {
+		t.Fatal("Unable to compile schema: ", err)
+	}
+
+	resp, err := Get(ts.URL, &RequestOptions{ResponseSchema: schema})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	err = resp.JSON(&struct{}{})
+
+	schemaErr, ok := err.(*SchemaValidationError)
+
+	if !ok {
+		t.Fatal("Expected a schema validation error: ", err)
+	}
+
+	locations := map[string]bool{}
+
+	for _, v := range schemaErr.Violations {
+		locations[v.InstanceLocation+" "+v.KeywordLocation] = true
+	}
+
+	if !locations["/id /properties/id/type"] || !locations[" /required"] {
+		t.Error("Violations were not reported: ", schemaErr.Violations)
+	}
+}
+
+func TestCompileInvalidJSONSchema(t *testing.T) {
+	if _, err := CompileJSONSchema([]byte(`{"type": 12}`)); err == nil {
+		t.Error("Invalid schema was compiled")
+	}
+}
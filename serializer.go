@@ -0,0 +1,126 @@
+package grequests
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Serializer encodes the Body of a request into a format such as protobuf, msgpack or CBOR
+// (see the packages within the serializer directory)
+type Serializer interface {
+	// ContentType is the Content-Type header of the encoded body
+	ContentType() string
+
+	// Encode encodes v into the body of the request
+	Encode(v interface{}) (io.Reader, error)
+}
+
+// BodyDecoder decodes the body of a response into v
+type BodyDecoder func(body io.Reader, v interface{}) error
+
+var (
+	serializersMu sync.RWMutex
+
+	// serializers maps a media type to the serializer that encodes it
+	serializers = map[string]Serializer{}
+
+	// bodyDecoders maps a media type to the decoder of the responses that use it
+	bodyDecoders = map[string]BodyDecoder{}
+)
+
+// RegisterSerializer registers the serializer under the media type of its ContentType so a
+// request can select it by setting the ContentType option (rather than the Serializer option).
+// Registering a serializer for a media type that already has one replaces it
+func RegisterSerializer(serializer Serializer) {
+	serializersMu.Lock()
+	defer serializersMu.Unlock()
+
+	serializers[normalizeMediaType(serializer.ContentType())] = serializer
+}
+
+// RegisterBodyDecoder adds a decoder for the response bodies of the media type (e.g.
+// application/msgpack). ScanBody decodes responses using the decoder of their Content-Type.
+// Registering a decoder for a media type that already has one replaces it. Passing a nil
+// decoder removes the media type
+func RegisterBodyDecoder(mediaType string, decoder BodyDecoder) {
+	mediaType = normalizeMediaType(mediaType)
+
+	serializersMu.Lock()
+	defer serializersMu.Unlock()
+
+	if decoder == nil {
+		delete(bodyDecoders, mediaType)
+		return
+	}
+
+	bodyDecoders[mediaType] = decoder
+}
+
+// lookupSerializer returns the serializer registered for the media type of the Content-Type
+func lookupSerializer(contentType string) (Serializer, bool) {
+	serializersMu.RLock()
+	defer serializersMu.RUnlock()
+
+	serializer, ok := serializers[normalizeMediaType(contentType)]
+
+	return serializer, ok
+}
+
+// lookupBodyDecoder returns the decoder registered for the media type
+func lookupBodyDecoder(mediaType string) (BodyDecoder, bool) {
+	serializersMu.RLock()
+	defer serializersMu.RUnlock()
+
+	decoder, ok := bodyDecoders[normalizeMediaType(mediaType)]
+
+	return decoder, ok
+}
+
+// normalizeMediaType returns the (lower case) media type of the Content-Type without its parameters
+func normalizeMediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+func createSerializedRequest(httpMethod, userURL string, ro *RequestOptions) (*http.Request, error) {
+	serializer := ro.Serializer
+
+	if serializer == nil {
+		var ok bool
+
+		if serializer, ok = lookupSerializer(ro.ContentType); !ok {
+			return nil, errors.New("grequests: No Serializer for the Body (set Serializer or the ContentType of a registered Serializer)")
+		}
+	}
+
+	body, err := serializer.Encode(ro.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(httpMethod, userURL, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ContentLength == 0 && req.Body != http.NoBody {
+		req.ContentLength = readerSize(body)
+	}
+
+	if req.GetBody == nil && req.Body != http.NoBody {
+		req.GetBody = bodyReopener(body)
+	}
+
+	req.Header.Set("Content-Type", serializer.ContentType())
+
+	return req, nil
+}
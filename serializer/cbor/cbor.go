@@ -0,0 +1,48 @@
+// Package cbor encodes request bodies and decodes response bodies using CBOR (RFC 8949).
+// Importing the package registers it for the application/cbor content type:
+//
+//	resp, err := grequests.Post(url, &grequests.RequestOptions{Body: order, Serializer: cbor.Serializer{}})
+//
+//	var created Order
+//	err = resp.ScanBody(&created)
+package cbor
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/levigross/grequests"
+)
+
+// ContentType is the Content-Type of the bodies encoded by Serializer
+const ContentType = "application/cbor"
+
+func init() {
+	grequests.RegisterSerializer(Serializer{})
+	grequests.RegisterBodyDecoder(ContentType, Decode)
+}
+
+// Serializer encodes a value as CBOR (using its `cbor` struct tags)
+type Serializer struct{}
+
+// ContentType implements grequests.Serializer
+func (Serializer) ContentType() string {
+	return ContentType
+}
+
+// Encode implements grequests.Serializer
+func (Serializer) Encode(v interface{}) (io.Reader, error) {
+	body, err := cbor.Marshal(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(body), nil
+}
+
+// Decode decodes the body into v
+func Decode(body io.Reader, v interface{}) error {
+	return cbor.NewDecoder(body).Decode(v)
+}
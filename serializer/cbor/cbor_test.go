@@ -0,0 +1,65 @@
+package cbor
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/levigross/grequests"
+)
+
+type order struct {
+	ID    int      `cbor:"id"`
+	Items []string `cbor:"items"`
+}
+
+// echoServer responds with the body (and Content-Type) of the request
+func echoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		io.Copy(w, r.Body)
+	}))
+}
+
+func TestSerializer(t *testing.T) {
+	ts := echoServer()
+	defer ts.Close()
+
+	resp, err := grequests.Post(ts.URL, &grequests.RequestOptions{Body: order{ID: 1, Items: []string{"a", "b"}}, Serializer: Serializer{}})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != ContentType {
+		t.Error("Unexpected Content-Type: ", contentType)
+	}
+
+	var echoed order
+
+	if err := resp.ScanBody(&echoed); err != nil {
+		t.Fatal("Unable to decode body: ", err)
+	}
+
+	if echoed.ID != 1 || len(echoed.Items) != 2 || echoed.Items[1] != "b" {
+		t.Errorf("Unexpected body: %+v", echoed)
+	}
+}
+
+func TestRegisteredContentType(t *testing.T) {
+	ts := echoServer()
+	defer ts.Close()
+
+	resp, err := grequests.Post(ts.URL, &grequests.RequestOptions{Body: map[string]int{"id": 2}, ContentType: ContentType})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	var echoed order
+
+	if err := resp.ScanBody(&echoed); err != nil || echoed.ID != 2 {
+		t.Errorf("Unexpected body %+v: %v", echoed, err)
+	}
+}
@@ -0,0 +1,49 @@
+// Package msgpack encodes request bodies and decodes response bodies using MessagePack. Importing
+// the package registers it for the application/msgpack (and application/x-msgpack) content types:
+//
+//	resp, err := grequests.Post(url, &grequests.RequestOptions{Body: order, Serializer: msgpack.Serializer{}})
+//
+//	var created Order
+//	err = resp.ScanBody(&created)
+package msgpack
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/levigross/grequests"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ContentType is the Content-Type of the bodies encoded by Serializer
+const ContentType = "application/msgpack"
+
+func init() {
+	grequests.RegisterSerializer(Serializer{})
+	grequests.RegisterBodyDecoder(ContentType, Decode)
+	grequests.RegisterBodyDecoder("application/x-msgpack", Decode)
+}
+
+// Serializer encodes a value as MessagePack (using its `msgpack` struct tags)
+type Serializer struct{}
+
+// ContentType implements grequests.Serializer
+func (Serializer) ContentType() string {
+	return ContentType
+}
+
+// Encode implements grequests.Serializer
+func (Serializer) Encode(v interface{}) (io.Reader, error) {
+	body, err := msgpack.Marshal(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(body), nil
+}
+
+// Decode decodes the body into v
+func Decode(body io.Reader, v interface{}) error {
+	return msgpack.NewDecoder(body).Decode(v)
+}
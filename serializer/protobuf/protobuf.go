@@ -0,0 +1,70 @@
+// Package protobuf encodes request bodies and decodes response bodies using Protocol Buffers.
+// Importing the package registers it for the application/x-protobuf (and application/protobuf)
+// content types:
+//
+//	resp, err := grequests.Post(url, &grequests.RequestOptions{Body: order, Serializer: protobuf.Serializer{}})
+//
+//	created := &pb.Order{}
+//	err = resp.ScanBody(created)
+package protobuf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/levigross/grequests"
+	"google.golang.org/protobuf/proto"
+)
+
+// ContentType is the Content-Type of the bodies encoded by Serializer
+const ContentType = "application/x-protobuf"
+
+func init() {
+	grequests.RegisterSerializer(Serializer{})
+	grequests.RegisterBodyDecoder(ContentType, Decode)
+	grequests.RegisterBodyDecoder("application/protobuf", Decode)
+}
+
+// Serializer encodes a proto.Message
+type Serializer struct{}
+
+// ContentType implements grequests.Serializer
+func (Serializer) ContentType() string {
+	return ContentType
+}
+
+// Encode implements grequests.Serializer
+func (Serializer) Encode(v interface{}) (io.Reader, error) {
+	message, ok := v.(proto.Message)
+
+	if !ok {
+		return nil, fmt.Errorf("grequests: Unable to encode %T as protobuf (it isn't a proto.Message)", v)
+	}
+
+	body, err := proto.Marshal(message)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(body), nil
+}
+
+// Decode decodes the body into v (which must be a proto.Message)
+func Decode(body io.Reader, v interface{}) error {
+	message, ok := v.(proto.Message)
+
+	if !ok {
+		return fmt.Errorf("grequests: Unable to decode protobuf into %T (it isn't a proto.Message)", v)
+	}
+
+	contents, err := ioutil.ReadAll(body)
+
+	if err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(contents, message)
+}
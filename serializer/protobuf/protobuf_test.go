@@ -0,0 +1,43 @@
+package protobuf
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/levigross/grequests"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestSerializer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		io.Copy(w, r.Body)
+	}))
+	defer ts.Close()
+
+	resp, err := grequests.Post(ts.URL, &grequests.RequestOptions{Body: wrapperspb.String("levi"), Serializer: Serializer{}})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != ContentType {
+		t.Error("Unexpected Content-Type: ", contentType)
+	}
+
+	echoed := &wrapperspb.StringValue{}
+
+	if err := resp.ScanBody(echoed); err != nil {
+		t.Fatal("Unable to decode body: ", err)
+	}
+
+	if echoed.GetValue() != "levi" {
+		t.Error("Unexpected body: ", echoed.GetValue())
+	}
+
+	if _, err := grequests.Post(ts.URL, &grequests.RequestOptions{Body: "not a message", Serializer: Serializer{}}); err == nil {
+		t.Error("A value that isn't a proto.Message was encoded")
+	}
+}
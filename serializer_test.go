@@ -0,0 +1,92 @@
+package grequests
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// csvSerializer encodes a [][]string as CSV (without quoting)
+type csvSerializer struct{}
+
+func (csvSerializer) ContentType() string {
+	return "text/csv"
+}
+
+func (csvSerializer) Encode(v interface{}) (io.Reader, error) {
+	rows, ok := v.([][]string)
+
+	if !ok {
+		return nil, fmt.Errorf("unable to encode %T", v)
+	}
+
+	body := &bytes.Buffer{}
+
+	for _, row := range rows {
+		body.WriteString(strings.Join(row, ",") + "\n")
+	}
+
+	return body, nil
+}
+
+func decodeCSV(body io.Reader, v interface{}) error {
+	contents, err := ioutil.ReadAll(body)
+
+	if err != nil {
+		return err
+	}
+
+	rows := v.(*[][]string)
+
+	for _, line := range strings.Split(strings.TrimSpace(string(contents)), "\n") {
+		*rows = append(*rows, strings.Split(line, ","))
+	}
+
+	return nil
+}
+
+func TestSerializer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type")+"; header=present")
+		io.Copy(w, r.Body)
+	}))
+	defer ts.Close()
+
+	body := [][]string{{"a", "b"}, {"c", "d"}}
+
+	if _, err := Post(ts.URL, &RequestOptions{Body: body, ContentType: "text/csv"}); err == nil {
+		t.Error("Body was sent without a serializer")
+	}
+
+	RegisterSerializer(csvSerializer{})
+	RegisterBodyDecoder("TEXT/CSV", decodeCSV)
+
+	defer func() {
+		serializersMu.Lock()
+		delete(serializers, "text/csv")
+		serializersMu.Unlock()
+
+		RegisterBodyDecoder("text/csv", nil)
+	}()
+
+	resp, err := Post(ts.URL, &RequestOptions{Body: body, ContentType: "text/csv; charset=utf-8"})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	var rows [][]string
+
+	if err := resp.ScanBody(&rows); err != nil {
+		t.Fatal("Unable to scan body: ", err)
+	}
+
+	if len(rows) != 2 || rows[1][1] != "d" {
+		t.Error("Unexpected rows: ", rows)
+	}
+}
@@ -1,6 +1,9 @@
 package grequests
 
-import "net/http"
+import (
+	"net/http"
+	"sync"
+)
 
 // Session allows a user to make use of persistent cookies in between
 // HTTP requests
@@ -8,6 +11,38 @@ type Session struct {
 
 	// HTTPClient is the client that we will use to request the resources
 	HTTPClient *http.Client
+
+	// BaseURL (if set) is the URL that relative URLs (e.g. "/users/42") are resolved against. The
+	// path is appended to the path of the BaseURL and the query string of the BaseURL is kept
+	BaseURL string
+
+	// BeforeRequest hooks are called for every request made using the session (ahead
+	// of the BeforeRequest hooks of the request itself)
+	BeforeRequest []func(*http.Request) error
+
+	// AfterResponse hooks are called for every response received by the session (ahead
+	// of the AfterResponse hooks of the request itself)
+	AfterResponse []func(*http.Response) error
+
+	// RateLimiter (if set) limits the rate of every request made using the session
+	// (unless the request has its own RateLimiter)
+	RateLimiter RateLimiter
+
+	// HARRecorder (if set) records every request sent by the session (and its response)
+	// so they can be exported as an HTTP Archive
+	HARRecorder *HARRecorder
+
+	// CircuitBreaker (if set) tracks the failures of each host the session sends
+	// requests to and short-circuits requests to hosts that keep failing (unless
+	// the request has its own CircuitBreaker)
+	CircuitBreaker *CircuitBreaker
+
+	// defaults are the options set by SetDefaultOptions
+	defaults   *RequestOptions
+	defaultsMu sync.RWMutex
+
+	// preconnector holds the connections established by Preconnect
+	preconnector *preconnector
 }
 
 // NewSession returns a session struct which enables can be used to maintain establish a persistent state with the
@@ -20,7 +55,14 @@ func NewSession(ro *RequestOptions) *Session {
 
 	ro.UseCookieJar = true
 
-	return &Session{HTTPClient: BuildHTTPClient(*ro)}
+	session := &Session{HTTPClient: BuildHTTPClient(*ro)}
+
+	// Only a transport built for the session can take the preconnected connections (see Preconnect)
+	if ro.HTTPClient == nil && ro.Transport == nil {
+		session.preconnector = newPreconnector(session.HTTPClient.Transport)
+	}
+
+	return session
 }
 
 // Get takes 2 parameters and returns a Response Struct. These two options are:
@@ -29,7 +71,7 @@ func NewSession(ro *RequestOptions) *Session {
 // If you do not intend to use the `RequestOptions` you can just pass nil
 // A new session is created by calling NewSession with a request options struct
 func (s *Session) Get(url string, ro *RequestOptions) (*Response, error) {
-	return doSessionRequest("GET", url, ro, s.HTTPClient)
+	return s.doRequest("GET", url, ro)
 }
 
 // Put takes 2 parameters and returns a Response struct. These two options are:
@@ -38,7 +80,7 @@ func (s *Session) Get(url string, ro *RequestOptions) (*Response, error) {
 // If you do not intend to use the `RequestOptions` you can just pass nil
 // A new session is created by calling NewSession with a request options struct
 func (s *Session) Put(url string, ro *RequestOptions) (*Response, error) {
-	return doSessionRequest("PUT", url, ro, s.HTTPClient)
+	return s.doRequest("PUT", url, ro)
 }
 
 // Patch takes 2 parameters and returns a Response struct. These two options are:
@@ -47,7 +89,7 @@ func (s *Session) Put(url string, ro *RequestOptions) (*Response, error) {
 // If you do not intend to use the `RequestOptions` you can just pass nil
 // A new session is created by calling NewSession with a request options struct
 func (s *Session) Patch(url string, ro *RequestOptions) (*Response, error) {
-	return doSessionRequest("PATCH", url, ro, s.HTTPClient)
+	return s.doRequest("PATCH", url, ro)
 }
 
 // Delete takes 2 parameters and returns a Response struct. These two options are:
@@ -56,7 +98,7 @@ func (s *Session) Patch(url string, ro *RequestOptions) (*Response, error) {
 // If you do not intend to use the `RequestOptions` you can just pass nil
 // A new session is created by calling NewSession with a request options struct
 func (s *Session) Delete(url string, ro *RequestOptions) (*Response, error) {
-	return doSessionRequest("DELETE", url, ro, s.HTTPClient)
+	return s.doRequest("DELETE", url, ro)
 }
 
 // Post takes 2 parameters and returns a Response channel. These two options are:
@@ -65,7 +107,7 @@ func (s *Session) Delete(url string, ro *RequestOptions) (*Response, error) {
 // If you do not intend to use the `RequestOptions` you can just pass nil
 // A new session is created by calling NewSession with a request options struct
 func (s *Session) Post(url string, ro *RequestOptions) (*Response, error) {
-	return doSessionRequest("POST", url, ro, s.HTTPClient)
+	return s.doRequest("POST", url, ro)
 }
 
 // Head takes 2 parameters and returns a Response channel. These two options are:
@@ -74,7 +116,7 @@ func (s *Session) Post(url string, ro *RequestOptions) (*Response, error) {
 // If you do not intend to use the `RequestOptions` you can just pass nil
 // A new session is created by calling NewSession with a request options struct
 func (s *Session) Head(url string, ro *RequestOptions) (*Response, error) {
-	return doSessionRequest("HEAD", url, ro, s.HTTPClient)
+	return s.doRequest("HEAD", url, ro)
 }
 
 // Options takes 2 parameters and returns a Response struct. These two options are:
@@ -83,10 +125,93 @@ func (s *Session) Head(url string, ro *RequestOptions) (*Response, error) {
 // If you do not intend to use the `RequestOptions` you can just pass nil
 // A new session is created by calling NewSession with a request options struct
 func (s *Session) Options(url string, ro *RequestOptions) (*Response, error) {
-	return doSessionRequest("OPTIONS", url, ro, s.HTTPClient)
+	return s.doRequest("OPTIONS", url, ro)
+}
+
+// Req takes 3 parameters and returns a Response struct. These three options are:
+// 	1. An HTTP verb (e.g. PROPFIND, REPORT, PURGE or LINK)
+// 	2. A URL
+// 	3. A RequestOptions struct
+// If you do not intend to use the `RequestOptions` you can just pass nil
+// A new session is created by calling NewSession with a request options struct
+func (s *Session) Req(verb string, url string, ro *RequestOptions) (*Response, error) {
+	return s.doRequest(verb, url, ro)
+}
+
+// Do sends a PreparedRequest (e.g. a request loaded from a HAR file) using the session
+func (s *Session) Do(pr *PreparedRequest) (*Response, error) {
+	return pr.send(s.HTTPClient, s.applySessionOptions(nil))
+}
+
+// doRequest sends the request using the client and the options of the session
+func (s *Session) doRequest(verb, url string, ro *RequestOptions) (*Response, error) {
+	var err error
+
+	ro = s.applySessionOptions(ro)
+
+	// The placeholders are substituted before the URL is joined with the BaseURL (which would encode them)
+	if ro != nil && len(ro.PathParams) != 0 && s.BaseURL != "" {
+		if url, err = buildURLPathParams(url, ro.PathParams); err != nil {
+			return buildResponse(nil, err)
+		}
+	}
+
+	if url, err = s.resolveURL(url); err != nil {
+		return buildResponse(nil, err)
+	}
+
+	return doSessionRequest(verb, url, ro, s.HTTPClient)
+}
+
+// resolveURL resolves the URL against the BaseURL of the session
+func (s *Session) resolveURL(rawURL string) (string, error) {
+	if s.BaseURL == "" {
+		return rawURL, nil
+	}
+
+	return joinURL(s.BaseURL, rawURL)
 }
 
 // CloseIdleConnections closes the idle connections that a session client may make use of
 func (s *Session) CloseIdleConnections() {
-	s.HTTPClient.Transport.(*http.Transport).CloseIdleConnections()
+	s.HTTPClient.CloseIdleConnections()
+
+	if s.preconnector != nil {
+		s.preconnector.parked.closeAll()
+	}
+}
+
+// applySessionOptions returns the request options with the default options and the options of the
+// session applied. The hooks of the session run ahead of the hooks of the request. The options of the
+// user are copied so they are never modified
+func (s *Session) applySessionOptions(ro *RequestOptions) *RequestOptions {
+	ro = s.withDefaultOptions(ro)
+
+	if len(s.BeforeRequest) == 0 && len(s.AfterResponse) == 0 && s.RateLimiter == nil && s.CircuitBreaker == nil &&
+		s.HARRecorder == nil {
+		return ro
+	}
+
+	if ro == nil {
+		ro = &RequestOptions{}
+	}
+
+	applied := *ro
+
+	applied.BeforeRequest = append(append([]func(*http.Request) error(nil), s.BeforeRequest...), ro.BeforeRequest...)
+	applied.AfterResponse = append(append([]func(*http.Response) error(nil), s.AfterResponse...), ro.AfterResponse...)
+
+	if applied.RateLimiter == nil {
+		applied.RateLimiter = s.RateLimiter
+	}
+
+	if applied.CircuitBreaker == nil {
+		applied.CircuitBreaker = s.CircuitBreaker
+	}
+
+	if applied.HARRecorder == nil {
+		applied.HARRecorder = s.HARRecorder
+	}
+
+	return &applied
 }
@@ -0,0 +1,135 @@
+package grequests
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ShadowOptions mirrors a percentage of requests to a secondary base URL (e.g. a new backend
+// that is being validated). Mirrored requests are sent asynchronously and never affect the
+// primary request
+type ShadowOptions struct {
+	// BaseURL is where the requests are mirrored to. The path of the original request is appended
+	// to the path of the BaseURL and the query string of the original request is kept
+	BaseURL string
+
+	// Percentage (0 – 100) of the requests that are mirrored
+	Percentage float64
+
+	// Compare (if set) is called with the primary and the shadow response once both have finished.
+	// When Compare is set the body of the primary response is buffered (as if Bytes() was called)
+	// so that both bodies can be compared. If Compare isn't set the shadow response is discarded
+	Compare func(primary, shadow *Response)
+
+	// HTTPClient (if set) is used to send the mirrored requests
+	HTTPClient *http.Client
+
+	// BaseURLTrusted is a flag that will forward all headers (including cookies) to the
+	// BaseURL. Otherwise, the Cookie header and the headers specified in `SensitiveHTTPHeaders`
+	// are removed from the mirrored request
+	BaseURLTrusted bool
+}
+
+// startShadowRequest mirrors the request (if it has been selected) and returns a function that must be
+// called with the primary response. nil is returned if the request isn't mirrored
+func startShadowRequest(req *http.Request, ro *RequestOptions) func(*Response) {
+	shadow := ro.Shadow
+
+	if shadow == nil || shadow.Percentage <= 0 || rand.Float64()*100 >= shadow.Percentage {
+		return nil
+	}
+
+	shadowReq, err := cloneShadowRequest(req, shadow.BaseURL)
+
+	if err != nil {
+		return nil
+	}
+
+	if !shadow.BaseURLTrusted {
+		removeSensitiveHeaders(shadowReq, ro)
+	}
+
+	httpClient := shadow.HTTPClient
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	primaryResponses := make(chan *Response, 1)
+
+	go func() {
+		shadowResp, _ := sendRequest(httpClient, shadowReq, &RequestOptions{})
+
+		if shadow.Compare == nil {
+			discardResponse(shadowResp)
+			return
+		}
+
+		shadowResp.Bytes()
+
+		shadow.Compare(<-primaryResponses, shadowResp)
+	}()
+
+	return func(primary *Response) {
+		if shadow.Compare != nil {
+			primary.Bytes()
+		}
+
+		primaryResponses <- primary
+	}
+}
+
+// cloneShadowRequest returns a copy of the request that is sent to the base URL
+func cloneShadowRequest(req *http.Request, baseURL string) (*http.Request, error) {
+	shadowURL, err := url.Parse(baseURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The shadow request must not be cancelled along with the primary request
+	shadowReq := req.Clone(context.Background())
+
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, errShadowBodyNotReplayable
+		}
+
+		if shadowReq.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The escaped paths are joined so that escaped characters (e.g. %2F) are kept
+	joinedPath, err := url.Parse(strings.TrimRight(shadowURL.EscapedPath(), "/") + req.URL.EscapedPath())
+
+	if err != nil {
+		return nil, err
+	}
+
+	shadowURL.Path, shadowURL.RawPath = joinedPath.Path, joinedPath.RawPath
+	shadowURL.RawQuery = req.URL.RawQuery
+
+	shadowReq.URL = shadowURL
+	shadowReq.Host = ""
+
+	return shadowReq, nil
+}
+
+// removeSensitiveHeaders removes the cookies and the sensitive headers (see SensitiveHTTPHeaders) from the request
+func removeSensitiveHeaders(req *http.Request, ro *RequestOptions) {
+	sensitiveHTTPHeaders := ro.SensitiveHTTPHeaders
+
+	if sensitiveHTTPHeaders == nil {
+		sensitiveHTTPHeaders = SensitiveHTTPHeaders
+	}
+
+	for header := range sensitiveHTTPHeaders {
+		req.Header.Del(header)
+	}
+
+	req.Header.Del("Cookie")
+}
@@ -0,0 +1,115 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestShadowMirrorsRequest(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("v1"))
+	}))
+	defer primary.Close()
+
+	shadowed := make(chan string, 1)
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		shadowed <- r.URL.Path + "?" + r.URL.RawQuery + " " + r.PostForm.Get("One")
+		w.Write([]byte("v2"))
+	}))
+	defer secondary.Close()
+
+	compared := make(chan [2]string, 1)
+
+	ro := &RequestOptions{
+		Data: map[string]string{"One": "Two"},
+		Shadow: &ShadowOptions{
+			BaseURL:    secondary.URL + "/v2/",
+			Percentage: 100,
+			Compare: func(primary, shadow *Response) {
+				compared <- [2]string{primary.String(), shadow.String()}
+			},
+		},
+	}
+
+	resp, err := Post(primary.URL+"/users?id=1", ro)
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "v1" {
+		t.Error("Primary response was modified: ", resp.String())
+	}
+
+	select {
+	case request := <-shadowed:
+		if request != "/v2/users?id=1 Two" {
+			t.Error("Request was not mirrored properly: ", request)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Request was not mirrored")
+	}
+
+	select {
+	case bodies := <-compared:
+		if bodies[0] != "v1" || bodies[1] != "v2" {
+			t.Error("Responses were not compared: ", bodies)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Compare was not called")
+	}
+}
+
+func TestShadowPercentageZero(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://httpbin.org/get", nil)
+
+	if startShadowRequest(req, &RequestOptions{Shadow: &ShadowOptions{BaseURL: "http://127.0.0.1"}}) != nil {
+		t.Error("Request was mirrored with a percentage of 0")
+	}
+}
+
+func TestShadowSensitiveHeaders(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer primary.Close()
+
+	shadowed := make(chan string, 1)
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		shadowed <- r.URL.EscapedPath() + " " + r.Header.Get("Authorization") + " " + r.Header.Get("Cookie") + " " + r.Header.Get("X-Custom")
+	}))
+	defer secondary.Close()
+
+	tests := []struct {
+		trusted  bool
+		expected string
+	}{
+		{false, "/v2/files/a%2Fb   custom"},
+		{true, "/v2/files/a%2Fb Bearer secret session=1 custom"},
+	}
+
+	for _, test := range tests {
+		_, err := Get(primary.URL+"/files/a%2Fb", &RequestOptions{
+			BearerToken: "secret",
+			Cookies:     []http.Cookie{{Name: "session", Value: "1"}},
+			Headers:     map[string]string{"X-Custom": "custom"},
+			Shadow:      &ShadowOptions{BaseURL: secondary.URL + "/v2", Percentage: 100, BaseURLTrusted: test.trusted},
+		})
+
+		if err != nil {
+			t.Fatal("Request failed: ", err)
+		}
+
+		select {
+		case request := <-shadowed:
+			if request != test.expected {
+				t.Errorf("Expected %q to be mirrored, got %q", test.expected, request)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Request was not mirrored")
+		}
+	}
+}
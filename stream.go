@@ -0,0 +1,267 @@
+package grequests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxStreamLineSize is the maximum size of a line returned by Lines (and of an event stream line)
+	maxStreamLineSize = 1 << 20
+
+	// defaultEventStreamRetry is how long StreamEvents waits before reconnecting unless the
+	// server sent a retry field
+	defaultEventStreamRetry = 3 * time.Second
+)
+
+// Lines returns a scanner that reads the body of the response one line at a time as it arrives
+// (e.g. for a JSON lines stream). Lines may be up to 1MB long (use scanner.Buffer to change this).
+// Close the response once you are done with it
+func (r *Response) Lines() *bufio.Scanner {
+	scanner := bufio.NewScanner(r.streamReader())
+	scanner.Buffer(nil, maxStreamLineSize)
+
+	return scanner
+}
+
+// JSONStream decodes the body of the response as a stream of JSON values (newline delimited or
+// simply concatenated) calling handle with each value as it arrives. JSONStream returns once the
+// body has been read or handle returns an error (which is returned)
+func (r *Response) JSONStream(handle func(json.RawMessage) error) error {
+	if r.Error != nil {
+		return r.Error
+	}
+
+	defer r.Close()
+
+	decoder := json.NewDecoder(r.getInternalReader())
+
+	for {
+		var value json.RawMessage
+
+		if err := decoder.Decode(&value); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := handle(value); err != nil {
+			return err
+		}
+	}
+}
+
+// Event is a Server-Sent Event
+type Event struct {
+	// ID is the ID of the event (or of the last event that had one)
+	ID string
+
+	// Event is the type of the event ("message" unless the server specified one)
+	Event string
+
+	// Data is the data of the event (the lines of a multi line event are joined with "\n")
+	Data string
+}
+
+// Events parses the body of the response as a text/event-stream calling handle with each event as
+// it arrives. Events returns once the body has been read or handle returns an error (which is returned).
+// Use StreamEvents to reconnect when the stream is interrupted
+func (r *Response) Events(handle func(Event) error) error {
+	if r.Error != nil {
+		return r.Error
+	}
+
+	defer r.Close()
+
+	return (&eventStreamParser{}).parse(r.getInternalReader(), handle)
+}
+
+// StreamEvents subscribes to the Server-Sent Events at the URL calling handle with each event. When
+// the stream ends (or the connection is lost) StreamEvents reconnects after the retry delay sent by
+// the server (3 seconds by default) sending the ID of the last event within the Last-Event-ID header.
+//
+// StreamEvents returns when handle returns an error (which is returned), the context is cancelled,
+// reconnecting fails or the server responds with a status that isn't 2xx (a 204 No Content tells
+// the client to stop and nil is returned). ro (which may be nil) is used for every request so a
+// RetryPolicy can be used to retry failed connection attempts
+func StreamEvents(ctx context.Context, url string, ro *RequestOptions, handle func(Event) error) error {
+	streamOptions := &RequestOptions{}
+
+	if ro != nil {
+		roCopy := *ro
+		streamOptions = &roCopy
+	}
+
+	streamOptions.Context = ctx
+
+	headers := map[string]string{"Accept": "text/event-stream", "Cache-Control": "no-cache"}
+
+	for key, value := range streamOptions.Headers {
+		headers[key] = value
+	}
+
+	parser := &eventStreamParser{retry: defaultEventStreamRetry}
+
+	for {
+		if parser.lastEventID != "" {
+			headers["Last-Event-ID"] = parser.lastEventID
+		}
+
+		streamOptions.Headers = headers
+
+		resp, err := Get(url, streamOptions)
+
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusNoContent {
+			resp.Close()
+			return nil
+		}
+
+		if err := resp.RaiseForStatus(); err != nil {
+			return err
+		}
+
+		var handleErr error
+
+		// An error reading the stream is treated like the end of the stream (we reconnect)
+		parser.parse(resp, func(event Event) error {
+			handleErr = handle(event)
+			return handleErr
+		})
+
+		resp.Close()
+
+		if handleErr != nil {
+			return handleErr
+		}
+
+		timer := time.NewTimer(parser.retry)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// eventStreamParser parses a text/event-stream. The last event ID and the retry delay are kept
+// across connections
+type eventStreamParser struct {
+	lastEventID string
+	retry       time.Duration
+}
+
+// parse calls handle with each event within the stream until the stream ends or handle returns an error
+func (p *eventStreamParser) parse(reader io.Reader, handle func(Event) error) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxStreamLineSize)
+	scanner.Split(scanEventStreamLines)
+
+	eventType := ""
+	data := &strings.Builder{}
+	first := true
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			first = false
+		}
+
+		// An empty line dispatches the event
+		if line == "" {
+			if data.Len() > 0 {
+				event := Event{ID: p.lastEventID, Event: eventType, Data: strings.TrimSuffix(data.String(), "\n")}
+
+				if event.Event == "" {
+					event.Event = "message"
+				}
+
+				if err := handle(event); err != nil {
+					return err
+				}
+			}
+
+			eventType = ""
+			data.Reset()
+			continue
+		}
+
+		// Lines starting with a colon are comments
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data.WriteString(value + "\n")
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				p.lastEventID = value
+			}
+		case "retry":
+			if milliseconds, err := strconv.ParseUint(value, 10, 63); err == nil {
+				p.retry = time.Duration(milliseconds) * time.Millisecond
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+// scanEventStreamLines is a bufio.SplitFunc that splits lines ending in "\r\n", "\n" or "\r"
+func scanEventStreamLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+
+		// A "\r" may be followed by a "\n" that hasn't been read yet
+		if i+1 == len(data) && !atEOF {
+			return 0, nil, nil
+		}
+
+		if i+1 < len(data) && data[i+1] == '\n' {
+			return i + 2, data[:i], nil
+		}
+
+		return i + 1, data[:i], nil
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
+
+// streamReader returns the body of the response (or a reader that fails with the error of the response)
+func (r *Response) streamReader() io.Reader {
+	if r.Error != nil {
+		return &errorReader{err: r.Error}
+	}
+
+	return r.getInternalReader()
+}
@@ -0,0 +1,204 @@
+package grequests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestResponseLines(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, "line %d\n", i)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Close()
+
+	var lines []string
+
+	for scanner := resp.Lines(); scanner.Scan(); {
+		lines = append(lines, scanner.Text())
+	}
+
+	if !reflect.DeepEqual(lines, []string{"line 1", "line 2", "line 3"}) {
+		t.Error("Unexpected lines: ", lines)
+	}
+}
+
+func TestResponseJSONStream(t *testing.T) {
+	// The second value is only sent once the first one has been handled
+	handled := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{\"type\":\"ADDED\"}\n"))
+		w.(http.Flusher).Flush()
+		<-handled
+		w.Write([]byte("{\"type\":\"DELETED\"}\n{\"type\":\"MODIFIED\"}"))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var types []string
+
+	err = resp.JSONStream(func(value json.RawMessage) error {
+		var event struct{ Type string }
+
+		if err := json.Unmarshal(value, &event); err != nil {
+			return err
+		}
+
+		if len(types) == 0 {
+			close(handled)
+		}
+
+		types = append(types, event.Type)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(types, []string{"ADDED", "DELETED", "MODIFIED"}) {
+		t.Error("Unexpected values: ", types)
+	}
+
+	stop := errors.New("stop")
+
+	resp, _ = Get(ts.URL, nil)
+
+	if err := resp.JSONStream(func(json.RawMessage) error { return stop }); err != stop {
+		t.Error("Expected the error of the handler, got: ", err)
+	}
+}
+
+func TestResponseEvents(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("\ufeff: comment\r\n" +
+			"data: first\r\n\r\n" +
+			"event: update\nid: 1\ndata: line 1\ndata:line 2\n\n" +
+			"id\rdata\r\r" +
+			"data: incomplete"))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []Event
+
+	err = resp.Events(func(event Event) error {
+		events = append(events, event)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Event{
+		{Event: "message", Data: "first"},
+		{ID: "1", Event: "update", Data: "line 1\nline 2"},
+		{Event: "message", Data: ""},
+	}
+
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Unexpected events: %+v", events)
+	}
+}
+
+func TestStreamEventsReconnects(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		connection := len(lastEventIDs)
+		mu.Unlock()
+
+		if r.Header.Get("Accept") != "text/event-stream" {
+			http.Error(w, "not an event stream request", http.StatusBadRequest)
+			return
+		}
+
+		switch connection {
+		case 1:
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "retry: 10\nid: 1\ndata: a\n\nid: 2\ndata: b\n\n")
+		case 2:
+			fmt.Fprint(w, "id: 3\ndata: c\n\n")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	var data []string
+
+	err := StreamEvents(context.Background(), ts.URL, nil, func(event Event) error {
+		data = append(data, event.ID+":"+event.Data)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(data, []string{"1:a", "2:b", "3:c"}) {
+		t.Error("Unexpected events: ", data)
+	}
+
+	if !reflect.DeepEqual(lastEventIDs, []string{"", "2", "3"}) {
+		t.Error("Unexpected Last-Event-ID headers: ", lastEventIDs)
+	}
+
+	// Cancelling the context stops the reconnection loop
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "retry: 10000\ndata: a\n\n")
+	}))
+	defer slow.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+
+	err = StreamEvents(ctx, slow.URL, nil, func(Event) error { return nil })
+
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(started) > time.Second {
+		t.Errorf("Expected the context to stop the stream, got %v after %s", err, time.Since(started))
+	}
+
+	// The error of the handler is returned
+	stop := errors.New("stop")
+
+	if err := StreamEvents(context.Background(), slow.URL, nil, func(Event) error { return stop }); err != stop {
+		t.Error("Expected the error of the handler, got: ", err)
+	}
+}
@@ -0,0 +1,142 @@
+package grequests
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings is the breakdown of the time spent on the last attempt of a request. Phases
+// that didn't happen (e.g. the DNS lookup and connect of a reused connection or the TLS
+// handshake of a plain HTTP request) are zero
+type Timings struct {
+	// DNSLookup is the time spent resolving the host
+	DNSLookup time.Duration
+
+	// Connect is the time spent establishing the TCP connection
+	Connect time.Duration
+
+	// TLSHandshake is the time spent on the TLS handshake
+	TLSHandshake time.Duration
+
+	// TTFB is the time from the start of the request until the first byte of the
+	// response was received
+	TTFB time.Duration
+
+	// Total is the time from the start of the request until the body was read completely
+	// (or closed). Until then it is the time until the response headers were received
+	Total time.Duration
+
+	// ConnReused is true if the request was sent over a previously used connection
+	ConnReused bool
+}
+
+// timingTrace collects the Timings of a single attempt
+type timingTrace struct {
+	mu sync.Mutex
+
+	start, dnsStart, connectStart, tlsStart time.Time
+
+	timings Timings
+}
+
+// newTimingTrace returns a context that records the timings of the request that it is
+// attached to. Any httptrace.ClientTrace within ctx is still called
+func newTimingTrace(ctx context.Context) (context.Context, *timingTrace) {
+	t := &timingTrace{start: time.Now()}
+
+	return httptrace.WithClientTrace(ctx, t.clientTrace()), t
+}
+
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.timings.ConnReused = info.Reused
+			t.mu.Unlock()
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dnsStart = time.Now()
+			t.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			t.timings.DNSLookup = time.Since(t.dnsStart)
+			t.mu.Unlock()
+		},
+		ConnectStart: func(network, addr string) {
+			t.mu.Lock()
+			// Several addresses may be dialed in parallel, we time from the first one
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mu.Lock()
+			if err == nil && t.timings.Connect == 0 {
+				t.timings.Connect = time.Since(t.connectStart)
+			}
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tlsStart = time.Now()
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			t.timings.TLSHandshake = time.Since(t.tlsStart)
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.timings.TTFB = time.Since(t.start)
+			t.mu.Unlock()
+		},
+	}
+}
+
+// finish sets the Timings of the response and updates Total once the body has been read
+func (t *timingTrace) finish(resp *Response) {
+	t.mu.Lock()
+	resp.Timings = t.timings
+	t.mu.Unlock()
+
+	resp.Timings.Total = time.Since(t.start)
+
+	if resp.RawResponse == nil {
+		return
+	}
+
+	resp.RawResponse.Body = &timedBody{ReadCloser: resp.RawResponse.Body, done: func() {
+		resp.Timings.Total = time.Since(t.start)
+	}}
+}
+
+// timedBody calls done once the body has been read completely or closed
+type timedBody struct {
+	io.ReadCloser
+	done func()
+	once sync.Once
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	if err == io.EOF {
+		b.once.Do(b.done)
+	}
+
+	return n, err
+}
+
+func (b *timedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.done)
+	return err
+}
@@ -0,0 +1,60 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+	"time"
+)
+
+func TestTimings(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("Hello"))
+	}))
+	defer ts.Close()
+
+	var gotConn bool
+
+	session := NewSession(&RequestOptions{InsecureSkipVerify: true})
+
+	resp, err := session.Get(ts.URL, &RequestOptions{Trace: &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) { gotConn = true },
+	}})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	if !gotConn {
+		t.Error("The user supplied trace was not called")
+	}
+
+	timings := resp.Timings
+
+	if timings.Connect <= 0 || timings.TLSHandshake <= 0 || timings.TTFB <= 0 || timings.ConnReused {
+		t.Errorf("Unexpected timings: %+v", timings)
+	}
+
+	if resp.String() != "Hello" {
+		t.Error("Unexpected body: ", resp.String())
+	}
+
+	if resp.Timings.Total < timings.TTFB+20*time.Millisecond {
+		t.Errorf("Total doesn't include reading the body: %+v", resp.Timings)
+	}
+
+	resp, err = session.Get(ts.URL, nil)
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	resp.Close()
+
+	if !resp.Timings.ConnReused || resp.Timings.Connect != 0 || resp.Timings.TLSHandshake != 0 {
+		t.Errorf("Unexpected timings for a reused connection: %+v", resp.Timings)
+	}
+}
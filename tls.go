@@ -0,0 +1,45 @@
+package grequests
+
+import (
+	"crypto/tls"
+	"sync"
+)
+
+// buildTLSConfig returns the TLS configuration of the transport built by BuildHTTPClient
+func (ro RequestOptions) buildTLSConfig() *tls.Config {
+	config := &tls.Config{
+		InsecureSkipVerify: ro.InsecureSkipVerify,
+		RootCAs:            ro.RootCAs,
+		Certificates:       ro.ClientCertificates,
+		ServerName:         ro.TLSServerName,
+	}
+
+	if ro.ClientCertFile != "" || ro.ClientKeyFile != "" {
+		config.GetClientCertificate = loadClientCertificate(ro.ClientCertFile, ro.ClientKeyFile)
+	}
+
+	return config
+}
+
+// loadClientCertificate returns a tls.Config.GetClientCertificate function which loads the PEM encoded
+// certificate and key the first time the server asks for a client certificate. An error loading the files
+// is returned from the TLS handshake (and so from the request)
+func loadClientCertificate(certFile, keyFile string) func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	var (
+		once sync.Once
+		cert tls.Certificate
+		err  error
+	)
+
+	return func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		once.Do(func() {
+			cert, err = tls.LoadX509KeyPair(certFile, keyFile)
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &cert, nil
+	}
+}
@@ -0,0 +1,157 @@
+package grequests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newClientCertificate returns a self signed client certificate along with its PEM encoded certificate and key
+func newClientCertificate(t *testing.T) (tls.Certificate, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "grequests client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, certPEM, keyPEM
+}
+
+// newMutualTLSServer returns a server that requires the client certificate and replies with its common name
+func newMutualTLSServer(t *testing.T, certPEM []byte) *httptest.Server {
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(certPEM)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	ts.StartTLS()
+
+	return ts
+}
+
+func TestClientCertificates(t *testing.T) {
+	cert, certPEM, _ := newClientCertificate(t)
+
+	ts := newMutualTLSServer(t, certPEM)
+	defer ts.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ts.Certificate())
+
+	resp, err := Get(ts.URL, &RequestOptions{ClientCertificates: []tls.Certificate{cert}, RootCAs: rootCAs})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "grequests client" {
+		t.Error("Client certificate was not presented: ", resp.String())
+	}
+
+	if _, err := Get(ts.URL, &RequestOptions{RootCAs: rootCAs}); err == nil {
+		t.Error("Request without a client certificate succeeded")
+	}
+}
+
+func TestClientCertFiles(t *testing.T) {
+	_, certPEM, keyPEM := newClientCertificate(t)
+
+	ts := newMutualTLSServer(t, certPEM)
+	defer ts.Close()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+
+	ioutil.WriteFile(certFile, certPEM, 0600)
+	ioutil.WriteFile(keyFile, keyPEM, 0600)
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ts.Certificate())
+
+	resp, err := Get(ts.URL, &RequestOptions{ClientCertFile: certFile, ClientKeyFile: keyFile, RootCAs: rootCAs})
+
+	if err != nil {
+		t.Fatal("Request failed: ", err)
+	}
+
+	if resp.String() != "grequests client" {
+		t.Error("Client certificate was not presented: ", resp.String())
+	}
+
+	_, err = Get(ts.URL, &RequestOptions{ClientCertFile: certFile, ClientKeyFile: "missing.key", RootCAs: rootCAs})
+
+	if err == nil {
+		t.Error("Missing key file did not fail the request")
+	}
+}
+
+func TestHostAndTLSServerName(t *testing.T) {
+	var host, serverName string
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, serverName = r.Host, r.TLS.ServerName
+	}))
+	defer ts.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ts.Certificate())
+
+	// The certificate of the test server is valid for example.com
+	resp, err := Get(ts.URL, &RequestOptions{RootCAs: rootCAs, Host: "www.example.com", TLSServerName: "example.com"})
+
+	if err != nil {
+		t.Fatal("Unable to make request: ", err)
+	}
+
+	resp.Close()
+
+	if host != "www.example.com" || serverName != "example.com" {
+		t.Errorf("Unexpected Host %q and server name %q", host, serverName)
+	}
+
+	if _, err := Get(ts.URL, &RequestOptions{RootCAs: rootCAs, TLSServerName: "example.org"}); err == nil {
+		t.Error("Certificate was not verified against the server name")
+	}
+}
@@ -0,0 +1,176 @@
+package grequests
+
+import (
+	"container/list"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCachedTransports is the maximum amount of transports that are kept (the least recently used
+// transport is dropped and its idle connections are closed)
+const maxCachedTransports = 32
+
+// sharedTransports holds the transports built for requests that aren't sent using a session so
+// requests with the same options reuse the same pool of connections
+var sharedTransports = newTransportCache(maxCachedTransports)
+
+// transportKey contains every option that affects the transport built by buildTransport
+type transportKey struct {
+	insecureSkipVerify    bool
+	disableCompression    bool
+	disableHTTP2          bool
+	forceHTTP2            bool
+	proxies               string
+	proxy                 string
+	tlsHandshakeTimeout   time.Duration
+	dialTimeout           time.Duration
+	dialKeepAlive         time.Duration
+	forceIPv4             bool
+	forceIPv6             bool
+	happyEyeballsDelay    time.Duration
+	responseHeaderTimeout time.Duration
+	clientCertFile        string
+	clientKeyFile         string
+	rootCAs               *x509.CertPool
+	tlsServerName         string
+	resolver              *net.Resolver
+	resolve               string
+	unixSocket            string
+	transport             http.RoundTripper
+	cache                 CacheBackend
+	cacheKey              *CacheKeyOptions
+}
+
+// transportKey returns the key of the transport of the options. Options that can't be compared
+// (functions, in memory certificates or transports that aren't comparable) can't be shared
+func (ro RequestOptions) transportKey() (transportKey, bool) {
+	if ro.ProxyFunc != nil || ro.WrapTransport != nil || len(ro.ClientCertificates) != 0 ||
+		!isComparable(ro.Transport) || !isComparable(ro.Cache) {
+		return transportKey{}, false
+	}
+
+	proxies := make(map[string]string, len(ro.Proxies))
+
+	for scheme, proxyURL := range ro.Proxies {
+		// A nil proxy (no proxy for the scheme) must not share a key with a missing scheme (the proxy
+		// of the environment is used)
+		if proxyURL == nil {
+			proxies[scheme] = "\x00nil"
+			continue
+		}
+
+		proxies[scheme] = proxyURL.String()
+	}
+
+	return transportKey{
+		insecureSkipVerify:    ro.InsecureSkipVerify,
+		disableCompression:    ro.DisableCompression,
+		disableHTTP2:          ro.DisableHTTP2,
+		forceHTTP2:            ro.ForceHTTP2,
+		proxies:               encodeSortedMap(proxies),
+		proxy:                 ro.Proxy,
+		tlsHandshakeTimeout:   ro.TLSHandshakeTimeout,
+		dialTimeout:           ro.DialTimeout,
+		dialKeepAlive:         ro.DialKeepAlive,
+		forceIPv4:             ro.ForceIPv4,
+		forceIPv6:             ro.ForceIPv6,
+		happyEyeballsDelay:    ro.HappyEyeballsDelay,
+		responseHeaderTimeout: ro.ResponseHeaderTimeout,
+		clientCertFile:        ro.ClientCertFile,
+		clientKeyFile:         ro.ClientKeyFile,
+		rootCAs:               ro.RootCAs,
+		tlsServerName:         ro.TLSServerName,
+		resolver:              ro.Resolver,
+		resolve:               encodeSortedMap(ro.Resolve),
+		unixSocket:            ro.UnixSocket,
+		transport:             ro.Transport,
+		cache:                 ro.Cache,
+		cacheKey:              ro.CacheKey,
+	}, true
+}
+
+// isComparable reports if the value can be used within a map key
+func isComparable(v interface{}) bool {
+	return v == nil || reflect.TypeOf(v).Comparable()
+}
+
+// encodeSortedMap encodes the map (sorted by key) into a string
+func encodeSortedMap(m map[string]string) string {
+	entries := make([]string, 0, len(m))
+
+	for _, key := range sortedKeys(m) {
+		entries = append(entries, key+"="+m[key])
+	}
+
+	return strings.Join(entries, "\n")
+}
+
+// cachedHTTPClient works like BuildHTTPClient except that the transport is shared with the previous
+// requests that had the same options (each client still gets its own cookie jar)
+func cachedHTTPClient(ro RequestOptions) *http.Client {
+	if ro.HTTPClient != nil || !ro.dontUseDefaultClient() {
+		return BuildHTTPClient(ro)
+	}
+
+	key, ok := ro.transportKey()
+
+	if !ok {
+		return BuildHTTPClient(ro)
+	}
+
+	return &http.Client{
+		Jar:       ro.cookieJar(),
+		Transport: sharedTransports.get(key, ro),
+	}
+}
+
+// transportCache is a least recently used cache of transports
+type transportCache struct {
+	capacity int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[transportKey]*list.Element
+}
+
+type transportCacheEntry struct {
+	key       transportKey
+	transport http.RoundTripper
+}
+
+func newTransportCache(capacity int) *transportCache {
+	return &transportCache{capacity: capacity, order: list.New(), entries: map[transportKey]*list.Element{}}
+}
+
+// get returns the transport of the key (building it from the options if it isn't cached)
+func (c *transportCache) get(key transportKey, ro RequestOptions) http.RoundTripper {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*transportCacheEntry).transport
+	}
+
+	entry := &transportCacheEntry{key: key, transport: ro.buildTransport()}
+	c.entries[key] = c.order.PushFront(entry)
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Remove(c.order.Back()).(*transportCacheEntry)
+		delete(c.entries, oldest.key)
+
+		// The transport of the user is left alone (it may still be used elsewhere)
+		if oldest.key.transport == nil {
+			if closer, ok := oldest.transport.(interface{ CloseIdleConnections() }); ok {
+				closer.CloseIdleConnections()
+			}
+		}
+	}
+
+	return entry.transport
+}
@@ -0,0 +1,113 @@
+package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTransportIsShared(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	ro := &RequestOptions{DialTimeout: 5 * time.Second, UseCookieJar: true}
+
+	for i := 0; i < 2; i++ {
+		resp, err := Get(ts.URL, ro)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.String() != "ok" {
+			t.Error("Unexpected body: ", resp.String())
+		}
+
+		if resp.Timings.ConnReused != (i == 1) {
+			t.Errorf("Request %d: expected ConnReused to be %t", i, i == 1)
+		}
+	}
+
+	first := cachedHTTPClient(RequestOptions{DialTimeout: time.Second, Headers: map[string]string{"a": "b"}})
+	second := cachedHTTPClient(RequestOptions{DialTimeout: time.Second, UseCookieJar: true})
+
+	if first.Transport != second.Transport {
+		t.Error("Expected requests with the same transport options to share a transport")
+	}
+
+	if first.Jar == second.Jar {
+		t.Error("Expected every client to have its own cookie jar")
+	}
+
+	if cachedHTTPClient(RequestOptions{DialTimeout: 2 * time.Second}).Transport == first.Transport {
+		t.Error("Expected requests with different options to have different transports")
+	}
+
+	proxyFunc := func(*http.Request) (*url.URL, error) { return nil, nil }
+
+	if cachedHTTPClient(RequestOptions{DialTimeout: time.Second, ProxyFunc: proxyFunc}).Transport == first.Transport {
+		t.Error("Expected a ProxyFunc to prevent the transport from being shared")
+	}
+
+	if cachedHTTPClient(RequestOptions{}) != http.DefaultClient {
+		t.Error("Expected the default client to be used without options")
+	}
+}
+
+func TestTransportCacheEviction(t *testing.T) {
+	cache := newTransportCache(2)
+
+	keys := []transportKey{{proxy: "a"}, {proxy: "b"}, {proxy: "c"}}
+
+	first := cache.get(keys[0], RequestOptions{})
+	cache.get(keys[1], RequestOptions{})
+
+	// Using the first transport makes the second one the least recently used
+	if cache.get(keys[0], RequestOptions{}) != first {
+		t.Error("Expected the transport to be cached")
+	}
+
+	cache.get(keys[2], RequestOptions{})
+
+	if _, ok := cache.entries[keys[1]]; ok || len(cache.entries) != 2 {
+		t.Error("Expected the least recently used transport to be evicted")
+	}
+
+	if cache.get(keys[0], RequestOptions{}) != first {
+		t.Error("Expected the recently used transport to be kept")
+	}
+}
+
+func TestTransportKeyNilProxy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	nilKey, ok := RequestOptions{Proxies: map[string]*url.URL{"http": nil}}.transportKey()
+
+	if !ok {
+		t.Fatal("Options with a nil proxy can't be shared")
+	}
+
+	if emptyKey, _ := (RequestOptions{Proxies: map[string]*url.URL{"http": {}}}).transportKey(); emptyKey == nilKey {
+		t.Error("A nil proxy shares its key with an empty proxy")
+	}
+
+	// A nil proxy means the scheme isn't proxied
+	resp, err := Get(ts.URL, &RequestOptions{Proxies: map[string]*url.URL{"http": nil, "gopher": nil}})
+
+	if err != nil || resp.String() != "ok" {
+		t.Error("Request with a nil proxy failed: ", err)
+	}
+
+	invalid := &url.URL{Scheme: "gopher", Host: "127.0.0.1:1"}
+
+	if _, err := Get(ts.URL, &RequestOptions{Proxies: map[string]*url.URL{"http": invalid}}); err == nil {
+		t.Error("Request through an invalid proxy succeeded")
+	}
+}
@@ -0,0 +1,382 @@
+package grequests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// Default value for MultipartUploader PartSize (the S3 minimum part size)
+	defaultUploadPartSize = 5 << 20
+
+	// Default value for MultipartUploader PartRetries
+	defaultUploadPartRetries = 3
+
+	// Default value for MultipartUploader RetryDelay
+	defaultUploadRetryDelay = time.Second
+
+	// Default value for MultipartUploader Concurrency
+	defaultUploadConcurrency = 4
+)
+
+// ErrUploadPartFailed is the error returned when a part of a multipart upload
+// could not be uploaded (even after retrying)
+var ErrUploadPartFailed = errors.New("grequests: Unable to upload part")
+
+// CompletedPart describes a part of a multipart upload that has been successfully uploaded
+type CompletedPart struct {
+	// PartNumber is the (1 based) number of the part
+	PartNumber int
+
+	// ETag is the ETag returned by the server for the part
+	ETag string
+
+	// Size is the amount of bytes within the part
+	Size int64
+}
+
+// MultipartUploader uploads a large object by splitting it into parts (in the style of S3 multipart
+// uploads). The initiate/upload part/complete flow is provided by the callbacks. At the very least
+// PartURL (or UploadPart) must be provided – the other callbacks are optional
+type MultipartUploader struct {
+	// PartSize is the size of each part in bytes. The last part may be smaller. The default is 5MB
+	PartSize int64
+
+	// PartRetries is the amount of times that we will retry uploading a part that failed. The
+	// default is 3. A negative number disables retries
+	PartRetries int
+
+	// RetryDelay is the amount of time to wait before retrying a part. The delay is multiplied by the
+	// attempt number. The default is 1 second
+	RetryDelay time.Duration
+
+	// Concurrency is the maximum amount of parts that UploadFrom will upload at the same time.
+	// The default is 4
+	Concurrency int
+
+	// Progress (if set) is called as the bytes of each part are sent. It may be called
+	// concurrently by UploadFrom. If a part is retried the sent count will start again from 0
+	Progress func(partNumber int, sent, size int64)
+
+	// RequestOptions are used for every part upload request (e.g. for headers, hooks, retries or an Authorizer)
+	RequestOptions *RequestOptions
+
+	// Initiate is called before any part is uploaded and returns the ID of the upload
+	Initiate func() (uploadID string, err error)
+
+	// PartURL returns the (usually presigned) URL that the part will be PUT to
+	PartURL func(uploadID string, partNumber int) (string, error)
+
+	// UploadPart overrides the default behaviour of PUTting the part to PartURL. It returns the ETag of the part
+	UploadPart func(uploadID string, partNumber int, body io.ReadSeeker, size int64) (etag string, err error)
+
+	// Complete is called once all of the parts have been uploaded
+	Complete func(uploadID string, parts []CompletedPart) error
+
+	// Abort is called if the upload fails after it has been initiated
+	Abort func(uploadID string) error
+}
+
+// Upload reads the contents of the reader in parts and uploads each part in order
+func (u *MultipartUploader) Upload(r io.Reader) ([]CompletedPart, error) {
+	if u.PartURL == nil && u.UploadPart == nil {
+		return nil, errors.New("grequests: MultipartUploader requires either PartURL or UploadPart")
+	}
+
+	uploadID, err := u.initiate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	partBuffer := make([]byte, u.partSize())
+
+	var parts []CompletedPart
+
+	for partNumber := 1; ; partNumber++ {
+		n, err := io.ReadFull(r, partBuffer)
+
+		if err == io.EOF && partNumber > 1 {
+			break
+		}
+
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, u.abort(uploadID, err)
+		}
+
+		part, uploadErr := u.uploadPartWithRetries(uploadID, partNumber, bytes.NewReader(partBuffer[:n]), int64(n))
+
+		if uploadErr != nil {
+			return nil, u.abort(uploadID, uploadErr)
+		}
+
+		parts = append(parts, part)
+
+		// A short read means that we have reached the end of the reader
+		if err != nil {
+			break
+		}
+	}
+
+	if u.Complete != nil {
+		if err := u.Complete(uploadID, parts); err != nil {
+			return nil, u.abort(uploadID, err)
+		}
+	}
+
+	return parts, nil
+}
+
+// UploadFrom uploads the first size bytes of the ReaderAt (e.g. an *os.File). As the source supports
+// random access, up to Concurrency parts are uploaded at the same time without buffering them in memory
+func (u *MultipartUploader) UploadFrom(r io.ReaderAt, size int64) ([]CompletedPart, error) {
+	if u.PartURL == nil && u.UploadPart == nil {
+		return nil, errors.New("grequests: MultipartUploader requires either PartURL or UploadPart")
+	}
+
+	uploadID, err := u.initiate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	partSize := u.partSize()
+
+	partCount := int((size + partSize - 1) / partSize)
+
+	// We always upload at least one (possibly empty) part
+	if partCount == 0 {
+		partCount = 1
+	}
+
+	parts := make([]CompletedPart, partCount)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		failed   = make(chan struct{})
+		sem      = make(chan struct{}, u.concurrency())
+	)
+
+	for i := 0; i < partCount; i++ {
+		sem <- struct{}{}
+
+		// Don't start any more parts once a part has failed
+		if isClosed(failed) {
+			<-sem
+			break
+		}
+
+		offset := int64(i) * partSize
+		length := partSize
+
+		if offset+length > size {
+			length = size - offset
+		}
+
+		wg.Add(1)
+
+		go func(i int, section *io.SectionReader) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			part, err := u.uploadPartWithRetries(uploadID, i+1, section, section.Size())
+
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					close(failed)
+				})
+				return
+			}
+
+			parts[i] = part
+		}(i, io.NewSectionReader(r, offset, length))
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, u.abort(uploadID, firstErr)
+	}
+
+	if u.Complete != nil {
+		if err := u.Complete(uploadID, parts); err != nil {
+			return nil, u.abort(uploadID, err)
+		}
+	}
+
+	return parts, nil
+}
+
+func (u *MultipartUploader) initiate() (string, error) {
+	if u.Initiate == nil {
+		return "", nil
+	}
+
+	return u.Initiate()
+}
+
+// abort aborts the upload and returns the error that caused the abort
+func (u *MultipartUploader) abort(uploadID string, cause error) error {
+	if u.Abort == nil {
+		return cause
+	}
+
+	if err := u.Abort(uploadID); err != nil {
+		return fmt.Errorf("%v (abort failed: %v)", cause, err)
+	}
+
+	return cause
+}
+
+func (u *MultipartUploader) partSize() int64 {
+	if u.PartSize <= 0 {
+		return defaultUploadPartSize
+	}
+
+	return u.PartSize
+}
+
+func (u *MultipartUploader) partRetries() int {
+	switch {
+	case u.PartRetries < 0:
+		return 0
+	case u.PartRetries == 0:
+		return defaultUploadPartRetries
+	}
+
+	return u.PartRetries
+}
+
+func (u *MultipartUploader) retryDelay() time.Duration {
+	if u.RetryDelay == 0 {
+		return defaultUploadRetryDelay
+	}
+
+	return u.RetryDelay
+}
+
+func (u *MultipartUploader) concurrency() int {
+	if u.Concurrency <= 0 {
+		return defaultUploadConcurrency
+	}
+
+	return u.Concurrency
+}
+
+func (u *MultipartUploader) uploadPartWithRetries(uploadID string, partNumber int, body io.ReadSeeker, size int64) (CompletedPart, error) {
+	var err error
+
+	// Parts sent by uploadPart report their progress through the UploadProgress option (which keeps
+	// the body rewindable for the retries of the request)
+	if u.Progress != nil && u.UploadPart != nil {
+		body = &progressReadSeeker{ReadSeeker: body, size: size, progress: func(sent, size int64) {
+			u.Progress(partNumber, sent, size)
+		}}
+	}
+
+	for attempt := 0; attempt <= u.partRetries(); attempt++ {
+		if attempt > 0 {
+			time.Sleep(u.retryDelay() * time.Duration(attempt))
+
+			if _, err = body.Seek(0, io.SeekStart); err != nil {
+				break
+			}
+		}
+
+		var etag string
+
+		if etag, err = u.uploadPart(uploadID, partNumber, body, size); err == nil {
+			return CompletedPart{PartNumber: partNumber, ETag: etag, Size: size}, nil
+		}
+	}
+
+	return CompletedPart{}, fmt.Errorf("%w %d: %v", ErrUploadPartFailed, partNumber, err)
+}
+
+func (u *MultipartUploader) uploadPart(uploadID string, partNumber int, body io.ReadSeeker, size int64) (string, error) {
+	if u.UploadPart != nil {
+		return u.UploadPart(uploadID, partNumber, body, size)
+	}
+
+	partURL, err := u.PartURL(uploadID, partNumber)
+
+	if err != nil {
+		return "", err
+	}
+
+	// The part is sent like any other request (sharing the cached client, retries, hooks and the Authorizer)
+	partOptions := &RequestOptions{}
+
+	if u.RequestOptions != nil {
+		roCopy := *u.RequestOptions
+		partOptions = &roCopy
+	}
+
+	partOptions.RequestBody = body
+
+	if u.Progress != nil {
+		partOptions.UploadProgress = func(sent, size int64) {
+			u.Progress(partNumber, sent, size)
+		}
+	}
+
+	resp, err := doRegularRequest("PUT", partURL, partOptions)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Close()
+
+	if !resp.Ok {
+		return "", fmt.Errorf("grequests: Part upload returned status code %d", resp.StatusCode)
+	}
+
+	return resp.Header.Get("ETag"), nil
+}
+
+// isClosed reports if the channel has been closed (without blocking)
+func isClosed(c chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
+
+// progressReadSeeker reports the amount of bytes that have been read. Seeking resets the count
+type progressReadSeeker struct {
+	io.ReadSeeker
+	sent     int64
+	size     int64
+	progress func(sent, size int64)
+}
+
+func (p *progressReadSeeker) Read(b []byte) (int, error) {
+	n, err := p.ReadSeeker.Read(b)
+
+	if n > 0 {
+		p.sent += int64(n)
+		p.progress(p.sent, p.size)
+	}
+
+	return n, err
+}
+
+func (p *progressReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	pos, err := p.ReadSeeker.Seek(offset, whence)
+
+	if err == nil {
+		p.sent = pos
+	}
+
+	return pos, err
+}
@@ -0,0 +1,259 @@
+package grequests
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMultipartUploaderUpload(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]string{}
+	failures := 1
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Fail the first request to exercise the retry logic
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		b, _ := ioutil.ReadAll(r.Body)
+		received[r.URL.Query().Get("partNumber")] = string(b)
+		w.Header().Set("ETag", `"etag-`+r.URL.Query().Get("partNumber")+`"`)
+	}))
+	defer ts.Close()
+
+	var completed []CompletedPart
+
+	u := &MultipartUploader{
+		PartSize:   4,
+		RetryDelay: 1,
+		Initiate:   func() (string, error) { return "upload-id", nil },
+		PartURL: func(uploadID string, partNumber int) (string, error) {
+			return ts.URL + "/?uploadId=" + uploadID + "&partNumber=" + strconv.Itoa(partNumber), nil
+		},
+		Complete: func(uploadID string, parts []CompletedPart) error {
+			completed = parts
+			return nil
+		},
+	}
+
+	parts, err := u.Upload(strings.NewReader("0123456789"))
+
+	if err != nil {
+		t.Fatal("Upload failed: ", err)
+	}
+
+	if len(parts) != 3 || len(completed) != 3 {
+		t.Fatal("Invalid amount of parts: ", parts)
+	}
+
+	if received["1"] != "0123" || received["2"] != "4567" || received["3"] != "89" {
+		t.Error("Parts were not split properly: ", received)
+	}
+
+	if parts[2].ETag != `"etag-3"` || parts[2].Size != 2 {
+		t.Error("Invalid completed part: ", parts[2])
+	}
+}
+
+func TestMultipartUploaderAbort(t *testing.T) {
+	aborted := false
+
+	u := &MultipartUploader{
+		PartSize:    4,
+		PartRetries: -1,
+		UploadPart: func(uploadID string, partNumber int, body io.ReadSeeker, size int64) (string, error) {
+			return "", errors.New("boom")
+		},
+		Abort: func(uploadID string) error {
+			aborted = true
+			return nil
+		},
+	}
+
+	if _, err := u.Upload(strings.NewReader("0123456789")); !errors.Is(err, ErrUploadPartFailed) {
+		t.Error("Expected a part upload error: ", err)
+	}
+
+	if !aborted {
+		t.Error("The upload was not aborted")
+	}
+}
+
+func TestMultipartUploaderUploadFrom(t *testing.T) {
+	var mu sync.Mutex
+	received := map[int]string{}
+	progress := map[int]int64{}
+
+	u := &MultipartUploader{
+		PartSize:    3,
+		Concurrency: 2,
+		UploadPart: func(uploadID string, partNumber int, body io.ReadSeeker, size int64) (string, error) {
+			b, err := ioutil.ReadAll(body)
+
+			if err != nil {
+				return "", err
+			}
+
+			mu.Lock()
+			received[partNumber] = string(b)
+			mu.Unlock()
+
+			return strconv.Itoa(partNumber), nil
+		},
+		Progress: func(partNumber int, sent, size int64) {
+			mu.Lock()
+			progress[partNumber] = sent
+			mu.Unlock()
+		},
+	}
+
+	parts, err := u.UploadFrom(strings.NewReader("abcdefgh"), 8)
+
+	if err != nil {
+		t.Fatal("Upload failed: ", err)
+	}
+
+	if len(parts) != 3 {
+		t.Fatal("Invalid amount of parts: ", parts)
+	}
+
+	if received[1] != "abc" || received[2] != "def" || received[3] != "gh" {
+		t.Error("Parts were not split properly: ", received)
+	}
+
+	if progress[1] != 3 || progress[3] != 2 {
+		t.Error("Progress was not reported: ", progress)
+	}
+
+	for i, part := range parts {
+		if part.PartNumber != i+1 || part.ETag != strconv.Itoa(i+1) {
+			t.Error("Parts are not in order: ", parts)
+		}
+	}
+}
+
+func TestMultipartUploaderUploadFromAbort(t *testing.T) {
+	aborted := false
+
+	u := &MultipartUploader{
+		PartSize:    2,
+		PartRetries: -1,
+		UploadPart: func(uploadID string, partNumber int, body io.ReadSeeker, size int64) (string, error) {
+			if partNumber == 2 {
+				return "", errors.New("boom")
+			}
+			return "etag", nil
+		},
+		Abort: func(uploadID string) error {
+			aborted = true
+			return nil
+		},
+	}
+
+	if _, err := u.UploadFrom(strings.NewReader("abcdefgh"), 8); !errors.Is(err, ErrUploadPartFailed) {
+		t.Error("Expected a part upload error: ", err)
+	}
+
+	if !aborted {
+		t.Error("The upload was not aborted")
+	}
+}
+
+func TestMultipartUploaderRequestOptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Hook") != "called" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("ETag", `"`+r.Header.Get("Content-Length")+`"`)
+	}))
+	defer ts.Close()
+
+	u := &MultipartUploader{
+		PartSize:    4,
+		PartRetries: -1,
+		PartURL: func(uploadID string, partNumber int) (string, error) {
+			return ts.URL, nil
+		},
+		RequestOptions: &RequestOptions{BeforeRequest: []func(*http.Request) error{
+			func(req *http.Request) error {
+				req.Header.Set("X-Hook", "called")
+				return nil
+			},
+		}},
+	}
+
+	parts, err := u.Upload(strings.NewReader("0123456789"))
+
+	if err != nil {
+		t.Fatal("Upload failed: ", err)
+	}
+
+	if len(parts) != 3 || parts[0].ETag != `"4"` || parts[2].ETag != `"2"` {
+		t.Error("Parts were not sent through the request pipeline: ", parts)
+	}
+}
+
+func TestMultipartUploaderRetryPolicy(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]string{}
+	progress := map[int]int64{}
+	failures := 1
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Fail the first part after it has been sent so the request retries it
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		received[r.URL.Query().Get("partNumber")] = string(b)
+	}))
+	defer ts.Close()
+
+	u := &MultipartUploader{
+		PartSize:    4,
+		PartRetries: -1,
+		PartURL: func(uploadID string, partNumber int) (string, error) {
+			return ts.URL + "/?partNumber=" + strconv.Itoa(partNumber), nil
+		},
+		Progress: func(partNumber int, sent, size int64) {
+			mu.Lock()
+			progress[partNumber] = sent
+			mu.Unlock()
+		},
+		RequestOptions: &RequestOptions{RetryPolicy: &BackoffRetryPolicy{Backoff: ConstantBackoff(0)}},
+	}
+
+	if _, err := u.UploadFrom(strings.NewReader("0123456789"), 10); err != nil {
+		t.Fatal("Upload failed: ", err)
+	}
+
+	if received["1"] != "0123" || received["2"] != "4567" || received["3"] != "89" {
+		t.Error("Parts were not retried properly: ", received)
+	}
+
+	if progress[1] != 4 || progress[3] != 2 {
+		t.Error("Progress was not reported: ", progress)
+	}
+}
@@ -1,9 +1,15 @@
 package grequests
 
 import (
+	"encoding/hex"
 	"errors"
 	"io"
 	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -25,6 +31,38 @@ var (
 	// with too many redirects
 	ErrRedirectLimitExceeded = errors.New("grequests: Request exceeded redirect count")
 
+	// ErrTooManyRedirects is an alias of ErrRedirectLimitExceeded
+	ErrTooManyRedirects = ErrRedirectLimitExceeded
+
+	// ErrTimeout is matched (using errors.Is) by the errors of requests that timed out
+	// e.g. because of the Context, AttemptTimeout, OverallDeadline or DialTimeout
+	ErrTimeout = errors.New("grequests: Request timed out")
+
+	// ErrFileUpload is matched (using errors.Is) by the errors of files that can't be uploaded
+	ErrFileUpload = errors.New("grequests: Unable to upload file")
+
+	// errShadowBodyNotReplayable is returned when a request can't be mirrored as its body can only be read once
+	errShadowBodyNotReplayable = errors.New("grequests: Request body cannot be mirrored")
+
+	// ErrResponseBodyTooLarge is the error returned when the (decoded) body of
+	// the response exceeded MaxResponseBodySize
+	ErrResponseBodyTooLarge = errors.New("grequests: Response body exceeded the maximum size")
+
+	// ErrCompressionRatioExceeded is the error returned when the body of the
+	// response decompressed to more than MaxCompressionRatio times its size
+	ErrCompressionRatioExceeded = errors.New("grequests: Response body exceeded the maximum compression ratio")
+
+	// ErrChecksumMismatch is matched (using errors.Is) by the *ChecksumError returned when the
+	// body of the response doesn't match its expected checksum
+	ErrChecksumMismatch = errors.New("grequests: Response body checksum mismatch")
+
+	// ErrPreconnectUnsupported is the error returned when Preconnect is used on a session that
+	// doesn't have its own *http.Transport
+	ErrPreconnectUnsupported = errors.New("grequests: Session transport does not support preconnecting")
+
+	// ErrInvalidPreconnectHost is the error returned when a host passed to Preconnect can't be dialed
+	ErrInvalidPreconnectHost = errors.New("grequests: Invalid host to preconnect to")
+
 	// RedirectLimit is a tunable variable that specifies how many times we can
 	// redirect in response to a redirect. This is the global variable, if you
 	// wish to set this on a request by request basis, set it within the
@@ -47,23 +85,32 @@ var (
 // because Go's XML library only supports XML encoded in UTF-8
 type XMLCharDecoder func(charset string, input io.Reader) (io.Reader, error)
 
-func addRedirectFunctionality(client *http.Client, ro *RequestOptions) {
-	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-		if ro.RedirectLimit == 0 {
-			ro.RedirectLimit = RedirectLimit
-		}
+// addRedirectFunctionality returns a shallow copy of the client that enforces the redirect limit and
+// strips sensitive headers on redirect. A copy is used so the client (which may be shared e.g.
+// http.DefaultClient or a Session client) isn't modified by concurrent requests
+func addRedirectFunctionality(client *http.Client, ro *RequestOptions) *http.Client {
+	redirectClient := *client
 
-		if len(via) >= ro.RedirectLimit {
-			return ErrRedirectLimitExceeded
-		}
+	redirectLimit := ro.RedirectLimit
+
+	if redirectLimit == 0 {
+		redirectLimit = RedirectLimit
+	}
+
+	sensitiveHTTPHeaders := ro.SensitiveHTTPHeaders
 
-		if ro.SensitiveHTTPHeaders == nil {
-			ro.SensitiveHTTPHeaders = SensitiveHTTPHeaders
+	if sensitiveHTTPHeaders == nil {
+		sensitiveHTTPHeaders = SensitiveHTTPHeaders
+	}
+
+	redirectClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= redirectLimit {
+			return ErrRedirectLimitExceeded
 		}
 
 		for k, vv := range via[0].Header {
 			// Is this a sensitive header?
-			if _, found := ro.SensitiveHTTPHeaders[k]; found && !ro.RedirectLocationTrusted {
+			if _, found := sensitiveHTTPHeaders[k]; found && !ro.RedirectLocationTrusted {
 				continue
 			}
 
@@ -74,4 +121,139 @@ func addRedirectFunctionality(client *http.Client, ro *RequestOptions) {
 
 		return nil
 	}
+
+	return &redirectClient
+}
+
+// sortedKeys returns the keys of the map in sorted order so that anything
+// built from the map is deterministic
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// sortedValueKeys returns the keys of the url.Values in sorted order
+func sortedValueKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// joinURL resolves the URL against the base URL. An absolute URL is returned as is, otherwise the
+// path is appended to the path of the base URL (it can't climb above it using "..") and the query
+// string is merged into the query string of the base URL (the values of the URL win)
+func joinURL(baseURL, rawURL string) (string, error) {
+	base, err := url.Parse(baseURL)
+
+	if err != nil {
+		return "", err
+	}
+
+	if ref, err := url.Parse(rawURL); err == nil && ref.Scheme != "" && ref.Host != "" {
+		return rawURL, nil
+	}
+
+	// The leading slash stops the first segment (e.g. "users:search") being parsed as a scheme
+	// and a leading "//" being parsed as a host
+	ref, err := url.Parse("/" + strings.TrimLeft(rawURL, "/"))
+
+	if err != nil {
+		return "", err
+	}
+
+	joined := *base
+
+	if refPath := ref.EscapedPath(); refPath != "/" {
+		cleaned := path.Clean(refPath)
+
+		if strings.HasSuffix(refPath, "/") {
+			cleaned = strings.TrimRight(cleaned, "/") + "/"
+		}
+
+		joinedPath, err := url.Parse(strings.TrimRight(base.EscapedPath(), "/") + cleaned)
+
+		if err != nil {
+			return "", err
+		}
+
+		joined.Path, joined.RawPath = joinedPath.Path, joinedPath.RawPath
+	}
+
+	switch {
+	case ref.RawQuery == "":
+	case base.RawQuery == "":
+		joined.RawQuery = ref.RawQuery
+	default:
+		query := base.Query()
+
+		for key, values := range ref.Query() {
+			query[key] = values
+		}
+
+		joined.RawQuery = query.Encode()
+	}
+
+	joined.Fragment, joined.RawFragment = ref.Fragment, ref.RawFragment
+
+	return joined.String(), nil
+}
+
+// percentEncode percent encodes every byte of s except the unreserved characters (RFC 3986)
+func percentEncode(s string) string {
+	encoded := &strings.Builder{}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~' {
+			encoded.WriteByte(c)
+			continue
+		}
+
+		encoded.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+	}
+
+	return encoded.String()
+}
+
+// parseRetryAfter parses the Retry-After header (which can either be the amount of seconds
+// to wait or an HTTP date)
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	retryAfter := strings.TrimSpace(header.Get("Retry-After"))
+
+	if retryAfter == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	retryTime, err := http.ParseTime(retryAfter)
+
+	if err != nil {
+		return 0, false
+	}
+
+	if delay := retryTime.Sub(time.Now()); delay > 0 {
+		return delay, true
+	}
+
+	return 0, true
 }
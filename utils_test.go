@@ -1 +1,89 @@
 package grequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		base     string
+		url      string
+		expected string
+	}{
+		{"https://api.example.com", "/users/42", "https://api.example.com/users/42"},
+		{"https://api.example.com/v1", "users/42", "https://api.example.com/v1/users/42"},
+		{"https://api.example.com/v1/", "/users/42/", "https://api.example.com/v1/users/42/"},
+		{"https://api.example.com/v1/", "", "https://api.example.com/v1/"},
+		{"https://api.example.com/v1", "/../../admin", "https://api.example.com/v1/admin"},
+		{"https://api.example.com/v1", "//evil.example.com/x", "https://api.example.com/v1/evil.example.com/x"},
+		{"https://api.example.com/v1", "/users:search", "https://api.example.com/v1/users:search"},
+		{"https://api.example.com/v1", "/a%2Fb", "https://api.example.com/v1/a%2Fb"},
+		{"https://api.example.com/v1?key=secret", "/users?page=2", "https://api.example.com/v1/users?key=secret&page=2"},
+		{"https://api.example.com/v1?key=secret&page=1", "/users?page=2", "https://api.example.com/v1/users?key=secret&page=2"},
+		{"https://api.example.com/v1?key=secret", "/users", "https://api.example.com/v1/users?key=secret"},
+		{"https://api.example.com/v1", "/users?page=2#top", "https://api.example.com/v1/users?page=2#top"},
+		{"https://api.example.com/v1", "https://other.example.com/x", "https://other.example.com/x"},
+	}
+
+	for _, test := range tests {
+		joined, err := joinURL(test.base, test.url)
+
+		if err != nil {
+			t.Errorf("%s + %s: %v", test.base, test.url, err)
+			continue
+		}
+
+		if joined != test.expected {
+			t.Errorf("%s + %s: expected %s got %s", test.base, test.url, test.expected, joined)
+		}
+	}
+
+	if _, err := joinURL("%gh&%ij", "/users"); err == nil {
+		t.Error("Expected an invalid base URL to fail")
+	}
+}
+
+func TestSessionBaseURL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "1", Path: "/api"})
+		w.Write([]byte(r.Method + " " + r.URL.String()))
+	}))
+	defer ts.Close()
+
+	session := NewSession(nil)
+	session.BaseURL = ts.URL + "/api?key=secret"
+
+	resp, err := session.Get("/users/42", &RequestOptions{Params: map[string]string{"fields": "name"}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.String() != "GET /api/users/42?fields=name&key=secret" {
+		t.Error("Unexpected request: ", resp.String())
+	}
+
+	resp, err = session.Req("PURGE", "cache", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.String() != "PURGE /api/cache?key=secret" {
+		t.Error("Unexpected request: ", resp.String())
+	}
+
+	cookies, err := session.Cookies("/users")
+
+	if err != nil || len(cookies) != 1 || cookies[0].Name != "session" {
+		t.Error("Unexpected cookies: ", cookies, err)
+	}
+
+	session.BaseURL = "%gh&%ij"
+
+	if resp, err := session.Get("/users/42", nil); err == nil || resp.Error != err {
+		t.Error("Expected an invalid BaseURL to fail, got: ", err)
+	}
+}
@@ -0,0 +1,240 @@
+package grequests
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// Default value for WebhookSender SignatureHeader
+	defaultWebhookSignatureHeader = "X-Webhook-Signature"
+
+	// Default value for WebhookSender TimestampHeader
+	defaultWebhookTimestampHeader = "X-Webhook-Timestamp"
+
+	// Default value for WebhookSender MaxAttempts
+	defaultWebhookMaxAttempts = 3
+
+	// Default value for WebhookSender Timeout
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// WebhookDelivery is the result of delivering a webhook
+type WebhookDelivery struct {
+	// URL is the destination of the webhook
+	URL string
+
+	// Delivered is true if the destination responded with a 2xx status code
+	Delivered bool
+
+	// StatusCode is the status code of the last attempt (0 if the last attempt failed)
+	StatusCode int
+
+	// Attempts is the amount of times we tried delivering the webhook
+	Attempts int
+
+	// Duration is how long the delivery took (including retries)
+	Duration time.Duration
+
+	// Error is the reason the delivery failed (if it failed)
+	Error error
+}
+
+// WebhookSender delivers signed webhooks. Every payload is signed with an HMAC-SHA256 of
+// "<timestamp>.<payload>" using the secret. The timestamp (unix seconds) is sent within the
+// TimestampHeader and the signature (as "sha256=<hex>") within the SignatureHeader. Failed
+// deliveries (network errors, 429 and 5xx responses) are retried with a backoff
+type WebhookSender struct {
+	// Session (if set) is used to deliver every webhook
+	Session *Session
+
+	// Secret is the key used to sign the payloads
+	Secret []byte
+
+	// SignatureHeader is the header that contains the signature. The default is X-Webhook-Signature
+	SignatureHeader string
+
+	// TimestampHeader is the header that contains the timestamp. The default is X-Webhook-Timestamp
+	TimestampHeader string
+
+	// MaxAttempts is the maximum amount of times we will try to deliver a webhook. The default is 3
+	MaxAttempts int
+
+	// Backoff decides how long to wait between attempts (a Retry-After header takes precedence).
+	// The default is an exponential backoff starting at 1 second
+	Backoff Backoff
+
+	// Timeout is the maximum amount of time a single attempt may take. The default is 10 seconds
+	Timeout time.Duration
+
+	// Timeouts overrides Timeout for specific destination hosts (e.g. "example.com:8443")
+	Timeouts map[string]time.Duration
+
+	// OnResult (if set) is called with the result of every delivery
+	OnResult func(WebhookDelivery)
+}
+
+// NewWebhookSender returns a WebhookSender that signs payloads using the secret
+func NewWebhookSender(secret []byte) *WebhookSender {
+	return &WebhookSender{Secret: secret}
+}
+
+// Send delivers the payload to the URL. A payload that is a []byte or string is sent as is,
+// anything else is encoded as JSON
+func (w *WebhookSender) Send(ctx context.Context, destination string, payload interface{}) WebhookDelivery {
+	delivery := w.send(ctx, destination, payload)
+
+	if w.OnResult != nil {
+		w.OnResult(delivery)
+	}
+
+	return delivery
+}
+
+func (w *WebhookSender) send(ctx context.Context, destination string, payload interface{}) WebhookDelivery {
+	delivery := WebhookDelivery{URL: destination}
+
+	body, err := encodeWebhookPayload(payload)
+
+	if err != nil {
+		delivery.Error = err
+		return delivery
+	}
+
+	req, err := http.NewRequest("POST", destination, bytes.NewReader(body))
+
+	if err != nil {
+		delivery.Error = err
+		return delivery
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(w.timestampHeader(), timestamp)
+	req.Header.Set(w.signatureHeader(), SignWebhookPayload(w.Secret, timestamp, body))
+
+	ro := &RequestOptions{
+		AttemptTimeout: w.timeout(req.URL),
+		RetryPolicy:    RetryPolicyFunc(w.shouldRetry),
+	}
+
+	addHTTPHeaders(ro, req)
+
+	httpClient := http.DefaultClient
+
+	if w.Session != nil {
+		httpClient = w.Session.HTTPClient
+	}
+
+	resp, err := sendRequest(addRedirectFunctionality(httpClient, ro), req.WithContext(ctx), ro)
+
+	delivery.Attempts = resp.Attempts
+	delivery.Duration = resp.Duration
+	delivery.StatusCode = resp.StatusCode
+
+	if err != nil {
+		delivery.Error = err
+		return delivery
+	}
+
+	discardResponse(resp)
+
+	if !resp.Ok {
+		delivery.Error = fmt.Errorf("grequests: Webhook delivery to %s failed with status code %d", destination, resp.StatusCode)
+		return delivery
+	}
+
+	delivery.Delivered = true
+
+	return delivery
+}
+
+// SignWebhookPayload returns the signature of a webhook payload ("sha256=" followed by the hex
+// encoded HMAC-SHA256 of "<timestamp>.<payload>"). Receivers can use it to verify a webhook
+func SignWebhookPayload(secret []byte, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func encodeWebhookPayload(payload interface{}) ([]byte, error) {
+	switch p := payload.(type) {
+	case []byte:
+		return p, nil
+	case string:
+		return []byte(p), nil
+	}
+
+	return json.Marshal(payload)
+}
+
+func (w *WebhookSender) shouldRetry(resp *Response, err error, attempt int) (time.Duration, bool) {
+	maxAttempts := w.MaxAttempts
+
+	if maxAttempts <= 0 {
+		maxAttempts = defaultWebhookMaxAttempts
+	}
+
+	if attempt >= maxAttempts {
+		return 0, false
+	}
+
+	if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return 0, false
+	}
+
+	if err == nil {
+		if delay, ok := parseRetryAfter(resp.Header); ok {
+			return delay, true
+		}
+	}
+
+	backoff := w.Backoff
+
+	if backoff == nil {
+		backoff = ExponentialBackoff(time.Second, 30*time.Second)
+	}
+
+	return backoff(attempt), true
+}
+
+func (w *WebhookSender) timeout(destination *url.URL) time.Duration {
+	if timeout, ok := w.Timeouts[destination.Host]; ok {
+		return timeout
+	}
+
+	if w.Timeout > 0 {
+		return w.Timeout
+	}
+
+	return defaultWebhookTimeout
+}
+
+func (w *WebhookSender) signatureHeader() string {
+	if w.SignatureHeader == "" {
+		return defaultWebhookSignatureHeader
+	}
+
+	return w.SignatureHeader
+}
+
+func (w *WebhookSender) timestampHeader() string {
+	if w.TimestampHeader == "" {
+		return defaultWebhookTimestampHeader
+	}
+
+	return w.TimestampHeader
+}
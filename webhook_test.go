@@ -0,0 +1,77 @@
+package grequests
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookSenderSignsAndRetries(t *testing.T) {
+	secret := []byte("shh")
+
+	var hits int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		expected := SignWebhookPayload(secret, r.Header.Get("X-Webhook-Timestamp"), body)
+
+		if r.Header.Get("X-Webhook-Signature") != expected || string(body) != `{"event":"created"}` {
+			t.Error("Invalid webhook: ", r.Header, string(body))
+		}
+
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	var result WebhookDelivery
+
+	sender := NewWebhookSender(secret)
+	sender.Backoff = ConstantBackoff(time.Millisecond)
+	sender.OnResult = func(d WebhookDelivery) { result = d }
+
+	delivery := sender.Send(context.Background(), ts.URL, map[string]string{"event": "created"})
+
+	if !delivery.Delivered || delivery.Error != nil {
+		t.Fatal("Webhook was not delivered: ", delivery.Error)
+	}
+
+	if delivery.Attempts != 2 || result.Attempts != 2 {
+		t.Error("Webhook was not retried: ", delivery)
+	}
+}
+
+func TestWebhookSenderGivesUp(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	sender := &WebhookSender{Secret: []byte("shh"), MaxAttempts: 2, Backoff: ConstantBackoff(0)}
+
+	delivery := sender.Send(context.Background(), ts.URL, []byte("{}"))
+
+	if delivery.Delivered || delivery.Error == nil || delivery.Attempts != 2 || delivery.StatusCode != 500 {
+		t.Error("Failed delivery was not reported: ", delivery)
+	}
+}
+
+func TestWebhookSenderPerDestinationTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer ts.Close()
+
+	sender := &WebhookSender{Secret: []byte("shh"), MaxAttempts: 1}
+	sender.Timeouts = map[string]time.Duration{ts.Listener.Addr().String(): 10 * time.Millisecond}
+
+	if delivery := sender.Send(context.Background(), ts.URL, "{}"); delivery.Error == nil {
+		t.Error("Per destination timeout was not enforced")
+	}
+}